@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// runFiles reports on each of files in turn, under a "==> name <==" heading
+// in the text format. With s.parallel the files are scheduled concurrently,
+// each into its own buffer, and the buffers are written out in file order so
+// the output matches a sequential run. The same goes for what each file
+// writes to stderr, its traces and warnings, headed by its name in plain
+// text, and for its -stats-file rows. The first failing file, in file order,
+// stops the output.
+func runFiles(w io.Writer, s settings, warnings warner, program string, files []string) error {
+	reports := make([]bytes.Buffer, len(files))
+	stderrs := make([]bytes.Buffer, len(files))
+	stats := make([][][]string, len(files))
+	errs := make([]error, len(files))
+	reportFile := func(i int) error {
+		fs, fw := s, warnings
+		fs.stderr, fw.w = &stderrs[i], &stderrs[i]
+		fs.pendingStats = &stats[i]
+		return report(&reports[i], fs, fw, program, files[i])
+	}
+
+	var wg sync.WaitGroup
+	for i := range files {
+		if !s.parallel {
+			if errs[i] = reportFile(i); errs[i] != nil {
+				break
+			}
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = reportFile(i)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range files {
+		if stderrs[i].Len() > 0 {
+			if !s.warningsJSON && (!s.trace || s.traceFormat != traceJSON) {
+				_, _ = fmt.Fprintf(s.stderr, "==> %s <==\n", files[i])
+			}
+			_, _ = stderrs[i].WriteTo(s.stderr)
+		}
+		if errs[i] != nil {
+			return fmt.Errorf("%s: %w", files[i], errs[i])
+		}
+		if stats[i] != nil {
+			if err := appendStats(s.statsFile, stats[i]); err != nil {
+				return err
+			}
+		}
+		if s.format == formatText {
+			if i > 0 {
+				_, _ = fmt.Fprintln(w)
+			}
+			_, _ = fmt.Fprintf(w, "==> %s <==\n", files[i])
+		}
+		if _, err := reports[i].WriteTo(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Test_runFiles_parallel is most useful under the race detector:
+// go test -race -run Test_runFiles_parallel
+func Test_runFiles_parallel(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	files := make([]string, 4)
+	for i := range files {
+		files[i] = filepath.Join(dir, fmt.Sprintf("workload%d.csv", i))
+		rows := fmt.Sprintf("1,%d,0,3\n2,4,1,1\n3,%d,2,2\n4,2,%d,1\n", 9-i, 3+i, 2*i)
+		if err := os.WriteFile(files[i], []byte(rows), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var sequential, parallel bytes.Buffer
+	if err := run(&sequential, append([]string{"binary_name"}, files...)...); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if err := run(&parallel, append([]string{"binary_name", "-parallel"}, files...)...); err != nil {
+		t.Fatalf("run(-parallel) error = %v", err)
+	}
+	if parallel.String() != sequential.String() {
+		t.Errorf("-parallel output differs from a sequential run:\n%s\nwant\n%s", parallel.String(), sequential.String())
+	}
+
+	last := -1
+	for _, file := range files {
+		at := strings.Index(sequential.String(), "==> "+file+" <==")
+		if at <= last {
+			t.Errorf("heading for %s missing or out of order", file)
+		}
+		last = at
+	}
+}
+
+func Test_runFiles_parallelStderrAndStats(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	files := make([]string, 4)
+	for i := range files {
+		files[i] = filepath.Join(dir, fmt.Sprintf("workload%d.csv", i))
+		rows := fmt.Sprintf("1,%d,0,3\n2,4,%d,1\n", 9-i, i)
+		if err := os.WriteFile(files[i], []byte(rows), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, parallel := range []bool{false, true} {
+		var out, stderr bytes.Buffer
+		s := defaultSettings()
+		s.parallel = parallel
+		s.trace = true
+		s.stderr = &stderr
+		s.statsFile = filepath.Join(dir, fmt.Sprintf("stats-%t.csv", parallel))
+		if err := runFiles(&out, s, warner{w: &stderr}, "binary_name", files); err != nil {
+			t.Fatalf("runFiles(parallel %t) error = %v", parallel, err)
+		}
+
+		// Each file's heading is followed by its own traces, one per algorithm.
+		sections := strings.Split(stderr.String(), "==> ")[1:]
+		if len(sections) != len(files) {
+			t.Fatalf("parallel %t: stderr has %d headings, want %d:\n%s", parallel, len(sections), len(files), stderr.String())
+		}
+		for i, section := range sections {
+			if !strings.HasPrefix(section, files[i]+" <==\n") {
+				t.Errorf("parallel %t: stderr section %d is %q, want %s", parallel, i, section, files[i])
+			}
+			if !strings.Contains(section, "dispatched=") {
+				t.Errorf("parallel %t: %s has no trace steps", parallel, files[i])
+			}
+		}
+
+		f, err := os.Open(s.statsFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		records, err := csv.NewReader(f).ReadAll()
+		_ = f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		perFile := (len(records) - 1) / len(files)
+		if perFile == 0 || 1+perFile*len(files) != len(records) {
+			t.Fatalf("parallel %t: got %d stats records, want a header and the same rows per file", parallel, len(records))
+		}
+		for j, row := range records[1:] {
+			if want := files[j/perFile]; row[0] != want {
+				t.Errorf("parallel %t: stats row %d is from %s, want %s", parallel, j, row[0], want)
+			}
+		}
+	}
+}
+
+func Test_schedule_leavesProcessesAlone(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 2, BurstDuration: 9, Priority: 3},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 4, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 1, BurstDuration: 1, Priority: 2},
+	}
+	want := append([]Process(nil), processes...)
+
+	for _, sc := range schedulers {
+		sc.schedule(processes, defaultSettings())
+		if !reflect.DeepEqual(processes, want) {
+			t.Fatalf("%s reordered its input to %v", sc.name, processes)
+		}
+	}
+}
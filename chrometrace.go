@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ChromeTraceEvent is a complete ("X") event in the Chrome Trace Event
+// Format. Pid is the algorithm's place in the run, from 1, so each algorithm
+// gets its own track group; Tid is the process ID. Times are in microseconds,
+// one per tick.
+type ChromeTraceEvent struct {
+	Name     string `json:"name"`
+	Category string `json:"cat"`
+	Phase    string `json:"ph"`
+	Pid      int    `json:"pid"`
+	Tid      int64  `json:"tid"`
+	Ts       int64  `json:"ts"`
+	Dur      int64  `json:"dur"`
+}
+
+// outputChromeTrace writes one event per Gantt slice of every result as a
+// {"traceEvents": [...]} document.
+func outputChromeTrace(w io.Writer, results []ScheduleResult) error {
+	events := make([]ChromeTraceEvent, 0)
+	for i, r := range results {
+		for _, slice := range r.Gantt {
+			events = append(events, ChromeTraceEvent{
+				Name:     fmt.Sprintf("P%d", slice.PID),
+				Category: r.Name,
+				Phase:    "X",
+				Pid:      i + 1,
+				Tid:      slice.PID,
+				Ts:       slice.Start,
+				Dur:      slice.Stop - slice.Start,
+			})
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(struct {
+		TraceEvents []ChromeTraceEvent `json:"traceEvents"`
+	}{events}); err != nil {
+		return fmt.Errorf("%w: encoding chrome trace", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func Test_outputChromeTrace(t *testing.T) {
+	t.Parallel()
+	var w bytes.Buffer
+	if err := run(&w, "binary_name", "-format", "chrome-trace", "example_processes.csv"); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	var trace struct {
+		TraceEvents []ChromeTraceEvent `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(w.Bytes(), &trace); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, w.String())
+	}
+
+	processes, err := readProcesses(defaultSettings(), warner{}, "binary_name", "example_processes.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var slices []TimeSlice
+	for _, r := range runSchedulers(processes, defaultSettings()) {
+		slices = append(slices, r.Gantt...)
+	}
+	if len(trace.TraceEvents) != len(slices) {
+		t.Fatalf("got %d events, want one per slice: %d", len(trace.TraceEvents), len(slices))
+	}
+	for i, event := range trace.TraceEvents {
+		if event.Phase != "X" || event.Tid != slices[i].PID || event.Ts != slices[i].Start ||
+			event.Dur != slices[i].Stop-slices[i].Start {
+			t.Errorf("event %d = %+v, want slice %+v", i, event, slices[i])
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// quantumComparison is one row of the -compare-quanta table: round-robin at
+// one quantum against the quantum-independent baselines.
+type quantumComparison struct {
+	Quantum       int64
+	RR, FCFS, SJF Metrics
+}
+
+// compareQuanta runs round-robin at each quantum and pairs it with the FCFS
+// and SJF metrics, taken from results when those algorithms already ran so
+// the baselines are computed only once.
+func compareQuanta(processes []Process, quanta []int64, results []ScheduleResult, s settings) []quantumComparison {
+	baseline := func(name string) Metrics {
+		for i := range results {
+			if results[i].Name == name {
+				return results[i].Metrics
+			}
+		}
+		sc, _ := lookupScheduler(name)
+		return sc.schedule(append([]Process(nil), processes...), s).Metrics
+	}
+	fcfs, sjf := baseline("fcfs"), baseline("sjf")
+
+	rr, _ := lookupScheduler("rr")
+	rows := make([]quantumComparison, len(quanta))
+	for i, q := range quanta {
+		qs := s
+		qs.quantum = q
+		rows[i] = quantumComparison{
+			Quantum: q,
+			RR:      rr.schedule(append([]Process(nil), processes...), qs).Metrics,
+			FCFS:    fcfs,
+			SJF:     sjf,
+		}
+	}
+
+	return rows
+}
+
+// outputQuantumComparison renders the -compare-quanta table.
+func outputQuantumComparison(w io.Writer, rows []quantumComparison, s settings) {
+	_, _ = fmt.Fprintln(w, "Round-robin quanta against FCFS and SJF")
+	table := newTable(w, s)
+	table.SetHeader([]string{
+		"Quantum", "RR wait", "RR turnaround", "FCFS wait", "FCFS turnaround", "SJF wait", "SJF turnaround",
+	})
+	for _, row := range rows {
+		table.Append([]string{
+			fmt.Sprint(row.Quantum),
+			formatAverage(row.RR.AverageWait, s),
+			formatAverage(row.RR.AverageTurnaround, s),
+			formatAverage(row.FCFS.AverageWait, s),
+			formatAverage(row.FCFS.AverageTurnaround, s),
+			formatAverage(row.SJF.AverageWait, s),
+			formatAverage(row.SJF.AverageTurnaround, s),
+		})
+	}
+	table.Render()
+}
+
+// parseQuanta parses a -compare-quanta list such as "2,4,8".
+func parseQuanta(v string) ([]int64, error) {
+	fields := strings.Split(v, ",")
+	quanta := make([]int64, len(fields))
+	for i, field := range fields {
+		q, err := strconv.ParseInt(strings.TrimSpace(field), 10, 64)
+		if err != nil || q < 1 {
+			return nil, fmt.Errorf("%w: quantum %q must be a positive whole number", ErrInvalidArgs, field)
+		}
+		quanta[i] = q
+	}
+
+	return quanta, nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_compareQuanta(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+	}
+	s := defaultSettings()
+	results := runSchedulers(append([]Process(nil), processes...), s)
+	rows := compareQuanta(processes, []int64{1, 2, 4, 8}, results, s)
+
+	if len(rows) != 4 {
+		t.Fatalf("got %d rows, want 4", len(rows))
+	}
+	for _, row := range rows[1:] {
+		if row.FCFS != rows[0].FCFS || row.SJF != rows[0].SJF {
+			t.Errorf("quantum %d baselines %+v, %+v differ from quantum %d's %+v, %+v",
+				row.Quantum, row.FCFS, row.SJF, rows[0].Quantum, rows[0].FCFS, rows[0].SJF)
+		}
+	}
+	if rows[0].RR == rows[3].RR {
+		t.Errorf("round-robin metrics did not change between quanta 1 and 8: %+v", rows[0].RR)
+	}
+
+	var w bytes.Buffer
+	outputQuantumComparison(&w, rows, s)
+	lines := strings.Split(strings.TrimSpace(w.String()), "\n")
+	// Rows sit between the header's closing border and the last border.
+	fcfsColumn := func(line string) string { return strings.Split(line, "|")[4] }
+	for _, line := range lines[5 : len(lines)-1] {
+		if fcfsColumn(line) != fcfsColumn(lines[4]) {
+			t.Errorf("FCFS wait column varies:\n%s", w.String())
+		}
+	}
+
+	if _, err := parseQuanta("2,0"); err == nil {
+		t.Error("parseQuanta() accepted a zero quantum")
+	}
+}
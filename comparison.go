@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// outputComparison renders one row of average metrics per result, for
+// comparing algorithms side by side.
+func outputComparison(w io.Writer, results []ScheduleResult, s settings) {
+	_, _ = fmt.Fprintln(w, "Algorithm comparison")
+	table := newTable(w, s)
+	table.SetHeader([]string{"Algorithm", "Wait", "Contention", "Turnaround", "Throughput", "Ready queue"})
+	table.SetColumnAlignment([]int{
+		tablewriter.ALIGN_LEFT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT,
+		tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT,
+	})
+	for _, r := range results {
+		table.Append([]string{
+			r.Title,
+			formatAverage(r.Metrics.AverageWait, s),
+			formatAverage(r.Metrics.AverageContentionWait, s),
+			formatAverage(r.Metrics.AverageTurnaround, s),
+			formatAverage(r.Metrics.Throughput, s) + "/t",
+			formatAverage(r.Metrics.AverageQueueLength, s),
+		})
+	}
+	table.Render()
+}
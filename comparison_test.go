@@ -0,0 +1,31 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_run_summaryTableOnly(t *testing.T) {
+	t.Parallel()
+	var w bytes.Buffer
+	if err := run(&w, "binary_name", "-summary-table-only", "-quantum-frac", "0.5", "example_processes.csv"); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	out := w.String()
+
+	for _, other := range []string{"Gantt schedule", "Schedule table", "Round-robin quantum"} {
+		if strings.Contains(out, other) {
+			t.Errorf("output contains %q:\n%s", other, out)
+		}
+	}
+	if !strings.HasPrefix(out, "Algorithm comparison\n") {
+		t.Errorf("output does not start with the comparison table:\n%s", out)
+	}
+	for _, name := range defaultAlgorithms {
+		sc, _ := lookupScheduler(name)
+		if n := strings.Count(out, "| "+sc.title+" "); n != 1 {
+			t.Errorf("%s appears in %d rows, want 1:\n%s", sc.title, n, out)
+		}
+	}
+}
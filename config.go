@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// configInput is the -config key naming the scheduling file, or files.
+const configInput = "input"
+
+// applyConfig reads the JSON object in the -config file at path and sets
+// every flag it names, e.g. {"algo": ["fcfs", "rr"], "quantum": 2}, through fs
+// exactly as if it had been given on the command line. Flags that were given
+// on the command line are left alone, so they override the file. Lists are
+// joined with commas. The files named by the "input" key are returned, for
+// use when the command line names none.
+func applyConfig(fs *flag.FlagSet, path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%v: error reading config file", err)
+	}
+	var config map[string]json.RawMessage
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("%w: config %s: %v", ErrInvalidArgs, path, err)
+	}
+
+	onCommandLine := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { onCommandLine[f.Name] = true })
+
+	var files []string
+	for name, raw := range config {
+		value, err := configValue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: config %s: %q: %v", ErrInvalidArgs, path, name, err)
+		}
+		switch {
+		case name == configInput:
+			files = strings.Split(value, ",")
+		case name == "config" || fs.Lookup(name) == nil:
+			return nil, fmt.Errorf("%w: config %s: unknown setting %q", ErrInvalidArgs, path, name)
+		case !onCommandLine[name]:
+			if err := fs.Set(name, value); err != nil {
+				return nil, fmt.Errorf("%w: config %s: %q: %v", ErrInvalidArgs, path, name, err)
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// configValue renders a config value the way it would be written as a flag:
+// strings as-is, numbers and booleans in JSON notation, and lists of those
+// joined with commas.
+func configValue(raw json.RawMessage) (string, error) {
+	var list []json.RawMessage
+	if json.Unmarshal(raw, &list) == nil {
+		values := make([]string, len(list))
+		for i := range list {
+			v, err := configScalar(list[i])
+			if err != nil {
+				return "", err
+			}
+			values[i] = v
+		}
+		return strings.Join(values, ","), nil
+	}
+
+	return configScalar(raw)
+}
+
+func configScalar(raw json.RawMessage) (string, error) {
+	var str string
+	if json.Unmarshal(raw, &str) == nil {
+		return str, nil
+	}
+	var num json.Number
+	if json.Unmarshal(raw, &num) == nil {
+		return num.String(), nil
+	}
+	var b bool
+	if json.Unmarshal(raw, &b) == nil {
+		return fmt.Sprint(b), nil
+	}
+
+	return "", fmt.Errorf("want a string, number, boolean or list of those, got %s", raw)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// outputDOT writes every result as a GraphViz digraph of dispatch
+// transitions: one cluster per algorithm, whose nodes are PIDs and whose
+// edges are context switches, in Gantt order, labelled with the time the
+// next process was dispatched. A process that runs again straight after
+// itself is not a switch, so it gets no edge.
+func outputDOT(w io.Writer, results []ScheduleResult) error {
+	bw := bufio.NewWriter(w)
+	_, _ = fmt.Fprintln(bw, "digraph schedule {")
+	for _, r := range results {
+		_, _ = fmt.Fprintf(bw, "\tsubgraph %q {\n", "cluster_"+r.Name)
+		_, _ = fmt.Fprintf(bw, "\t\tlabel=%q;\n", r.Title)
+
+		seen := make(map[int64]bool)
+		for _, slice := range r.Gantt {
+			if !seen[slice.PID] {
+				seen[slice.PID] = true
+				_, _ = fmt.Fprintf(bw, "\t\t%q [label=%q];\n", dotNode(r, slice.PID), fmt.Sprintf("P%d", slice.PID))
+			}
+		}
+		for i := 1; i < len(r.Gantt); i++ {
+			if r.Gantt[i].PID == r.Gantt[i-1].PID {
+				continue
+			}
+			_, _ = fmt.Fprintf(bw, "\t\t%q -> %q [label=%q];\n", dotNode(r, r.Gantt[i-1].PID),
+				dotNode(r, r.Gantt[i].PID), fmt.Sprint(r.Gantt[i].Start))
+		}
+		_, _ = fmt.Fprintln(bw, "\t}")
+	}
+	_, _ = fmt.Fprintln(bw, "}")
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("%w: writing DOT", err)
+	}
+
+	return nil
+}
+
+// dotNode names pid's node within r's cluster; node names are global to a
+// graph, so each algorithm needs its own.
+func dotNode(r ScheduleResult, pid int64) string {
+	return fmt.Sprintf("%s P%d", r.Name, pid)
+}
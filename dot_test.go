@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func Test_outputDOT(t *testing.T) {
+	t.Parallel()
+	var w bytes.Buffer
+	if err := run(&w, "binary_name", "-format", "dot", "example_processes.csv"); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	out := w.String()
+	if !strings.HasPrefix(out, "digraph schedule {\n") || !strings.HasSuffix(out, "}\n") {
+		t.Fatalf("output is not a digraph:\n%s", out)
+	}
+
+	processes, err := readProcesses(defaultSettings(), warner{}, "binary_name", "example_processes.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var switches int
+	for _, r := range runSchedulers(processes, defaultSettings()) {
+		switches += contextSwitches(r.Gantt)
+		for i := 1; i < len(r.Gantt); i++ {
+			if r.Gantt[i].PID == r.Gantt[i-1].PID {
+				continue
+			}
+			edge := fmt.Sprintf("%q -> %q [label=%q];", dotNode(r, r.Gantt[i-1].PID),
+				dotNode(r, r.Gantt[i].PID), fmt.Sprint(r.Gantt[i].Start))
+			if !strings.Contains(out, edge) {
+				t.Errorf("output has no edge %s:\n%s", edge, out)
+			}
+		}
+	}
+	if got := strings.Count(out, " -> "); got != switches {
+		t.Errorf("got %d edges, want one per context switch: %d\n%s", got, switches, out)
+	}
+}
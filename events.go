@@ -0,0 +1,80 @@
+package main
+
+import "sort"
+
+// EventKind says what happened to the CPU at an Event.
+type EventKind string
+
+// Kinds of Event.
+const (
+	EventStart EventKind = "start"
+	EventStop  EventKind = "stop"
+	// EventIdle marks the CPU falling idle; the idle spell lasts until the
+	// next EventStart.
+	EventIdle EventKind = "idle"
+)
+
+// Event is one moment of a schedule, for replaying it step by step, e.g. in
+// an animation. PID is 0 for EventIdle.
+type Event struct {
+	Time int64     `json:"time"`
+	PID  int64     `json:"pid"`
+	Kind EventKind `json:"kind"`
+}
+
+// GanttEvents flattens the result's Gantt chart into events in time order.
+// Every slice yields a start and a matching stop; at a shared instant the stop
+// comes before the next start. An idle event precedes any gap in the chart,
+// including one before the first slice.
+func GanttEvents(result ScheduleResult) []Event {
+	events := make([]Event, 0, 2*len(result.Gantt))
+	var lastStop int64
+	for _, slice := range result.Gantt {
+		if slice.Start > lastStop {
+			events = append(events, Event{Time: lastStop, Kind: EventIdle})
+		}
+		events = append(events,
+			Event{Time: slice.Start, PID: slice.PID, Kind: EventStart},
+			Event{Time: slice.Stop, PID: slice.PID, Kind: EventStop},
+		)
+		if slice.Stop > lastStop {
+			lastStop = slice.Stop
+		}
+	}
+
+	return events
+}
+
+// ExitOrder returns the PIDs of the result's processes in the order they
+// completed, judged by the stop of each process's last slice, so a process
+// round-robin runs in several slices counts once, at its final one. Processes
+// a -max-time cap left unfinished never completed and are left out. Processes
+// completing at the same tick keep the order of their final slices.
+func ExitOrder(result ScheduleResult) []int64 {
+	unfinished := make(map[int64]bool)
+	for _, row := range result.Rows {
+		if row.Unfinished {
+			unfinished[row.ProcessID] = true
+		}
+	}
+	last := make(map[int64]int)
+	for i, slice := range result.Gantt {
+		if !unfinished[slice.PID] {
+			last[slice.PID] = i
+		}
+	}
+
+	order := make([]int64, 0, len(last))
+	for pid := range last {
+		order = append(order, pid)
+	}
+	sort.Slice(order, func(i, j int) bool {
+		a, b := result.Gantt[last[order[i]]], result.Gantt[last[order[j]]]
+		if a.Stop != b.Stop {
+			return a.Stop < b.Stop
+		}
+		return last[order[i]] < last[order[j]]
+	})
+
+	return order
+}
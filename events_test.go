@@ -0,0 +1,103 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGanttEvents(t *testing.T) {
+	t.Parallel()
+	processes := func() []Process {
+		return []Process{
+			{ProcessID: 1, ArrivalTime: 2, BurstDuration: 5, Priority: 2},
+			{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+			{ProcessID: 3, ArrivalTime: 30, BurstDuration: 6, Priority: 3},
+		}
+	}
+	for _, sc := range schedulers {
+		sc := sc
+		t.Run(sc.name, func(t *testing.T) {
+			t.Parallel()
+			r := sc.schedule(processes(), defaultSettings())
+			events := GanttEvents(r)
+
+			running := make(map[int64]bool)
+			for i, e := range events {
+				if i > 0 && e.Time < events[i-1].Time {
+					t.Fatalf("event %d at %d comes after one at %d", i, e.Time, events[i-1].Time)
+				}
+				switch e.Kind {
+				case EventStart:
+					if running[e.PID] {
+						t.Fatalf("PID %d started twice at %d", e.PID, e.Time)
+					}
+					running[e.PID] = true
+				case EventStop:
+					if !running[e.PID] {
+						t.Fatalf("PID %d stopped at %d without starting", e.PID, e.Time)
+					}
+					running[e.PID] = false
+				}
+			}
+			for pid, open := range running {
+				if open {
+					t.Errorf("PID %d never stopped", pid)
+				}
+			}
+		})
+	}
+
+	t.Run("idle gaps", func(t *testing.T) {
+		t.Parallel()
+		r := ScheduleResult{Gantt: []TimeSlice{{PID: 1, Start: 2, Stop: 4}, {PID: 2, Start: 6, Stop: 7}}}
+		want := []Event{
+			{Time: 0, Kind: EventIdle},
+			{Time: 2, PID: 1, Kind: EventStart},
+			{Time: 4, PID: 1, Kind: EventStop},
+			{Time: 4, Kind: EventIdle},
+			{Time: 6, PID: 2, Kind: EventStart},
+			{Time: 7, PID: 2, Kind: EventStop},
+		}
+		if got := GanttEvents(r); !reflect.DeepEqual(got, want) {
+			t.Errorf("GanttEvents() = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestExitOrder(t *testing.T) {
+	t.Parallel()
+	// All arrive together, so FCFS keeps file order while SJF runs the
+	// shortest burst first.
+	processes := func() []Process {
+		return []Process{
+			{ProcessID: 1, BurstDuration: 8},
+			{ProcessID: 2, BurstDuration: 2},
+			{ProcessID: 3, BurstDuration: 5},
+		}
+	}
+	tests := []struct {
+		algorithm string
+		maxTime   int64
+		want      []int64
+	}{
+		{algorithm: "fcfs", want: []int64{1, 2, 3}},
+		{algorithm: "sjf", want: []int64{2, 3, 1}},
+		// Slices 1:0-4 2:4-6 3:6-10 1:10-14 3:14-15: 1 and 3 each count at
+		// their final slice.
+		{algorithm: "rr", want: []int64{2, 1, 3}},
+		// Capped at 10, 1 never finishes.
+		{algorithm: "sjf", maxTime: 10, want: []int64{2, 3}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.algorithm, func(t *testing.T) {
+			t.Parallel()
+			s := defaultSettings()
+			s.maxTime = tt.maxTime
+			sc, _ := lookupScheduler(tt.algorithm)
+			if got := ExitOrder(sc.schedule(processes(), s)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExitOrder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// Distributions accepted by -burst-dist and -arrival-dist.
+const (
+	distUniform     = "uniform"
+	distNormal      = "normal"
+	distExponential = "exponential"
+)
+
+// maxDistParam bounds every distribution parameter, in ticks, so draws and
+// the arrival clock they advance stay far from overflowing an int64.
+const maxDistParam = 1e9
+
+// distribution is a parsed -burst-dist or -arrival-dist spec such as
+// normal:10,3. params holds min and max for uniform, mean and standard
+// deviation for normal, and the mean for exponential.
+type distribution struct {
+	kind   string
+	params []float64
+}
+
+// parseDistribution parses a spec of the form kind:p1[,p2].
+func parseDistribution(spec string) (distribution, error) {
+	kind, list, _ := strings.Cut(spec, ":")
+	want := map[string]int{distUniform: 2, distNormal: 2, distExponential: 1}[kind]
+	if want == 0 {
+		return distribution{}, fmt.Errorf("%w: unknown distribution %q, want uniform, normal or exponential",
+			ErrInvalidArgs, kind)
+	}
+
+	d := distribution{kind: kind}
+	for _, field := range strings.Split(list, ",") {
+		v, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil || v < 0 || v > maxDistParam {
+			return distribution{}, fmt.Errorf("%w: distribution %q: %q is not a number from 0 to %.0f",
+				ErrInvalidArgs, spec, field, maxDistParam)
+		}
+		d.params = append(d.params, v)
+	}
+	switch {
+	case len(d.params) != want:
+		return distribution{}, fmt.Errorf("%w: distribution %q takes %d parameters", ErrInvalidArgs, spec, want)
+	case kind == distUniform && d.params[0] > d.params[1]:
+		return distribution{}, fmt.Errorf("%w: distribution %q has min above max", ErrInvalidArgs, spec)
+	case kind == distExponential && d.params[0] == 0:
+		return distribution{}, fmt.Errorf("%w: distribution %q needs a positive mean", ErrInvalidArgs, spec)
+	}
+
+	return d, nil
+}
+
+// sample draws one value from d using rng.
+func (d distribution) sample(rng *rand.Rand) float64 {
+	switch d.kind {
+	case distUniform:
+		return d.params[0] + rng.Float64()*(d.params[1]-d.params[0])
+	case distNormal:
+		return d.params[0] + rng.NormFloat64()*d.params[1]
+	default:
+		return rng.ExpFloat64() * d.params[0]
+	}
+}
+
+// generateFromDistributions returns n processes with IDs 1..n whose bursts
+// are drawn from burst and whose inter-arrival gaps are drawn from arrival,
+// or who all arrive at 0 when arrival is nil. Bursts are rounded up to whole
+// ticks and at least 1; negative draws, which a normal distribution can
+// make, count as 0 gaps. The same seed always yields the same workload.
+func generateFromDistributions(burst distribution, arrival *distribution, n int, seed int64) []Process {
+	rng := rand.New(rand.NewSource(seed))
+	processes := make([]Process, n)
+
+	var clock float64
+	for i := range processes {
+		if arrival != nil {
+			clock += math.Max(0, arrival.sample(rng))
+		}
+		processes[i] = Process{
+			ProcessID:     int64(i + 1),
+			ArrivalTime:   toTicks(clock),
+			BurstDuration: toTicks(math.Max(1, math.Ceil(burst.sample(rng)))),
+		}
+	}
+
+	return processes
+}
+
+// toTicks converts a non-negative draw to whole ticks, saturating at the
+// largest int64 instead of wrapping, which a distribution's far tail could
+// otherwise make it do.
+func toTicks(v float64) int64 {
+	if v >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+
+	return int64(v)
+}
+
+// Adversarial workloads accepted by -workload.
+const (
+	workloadConvoy  = "convoy"
+	workloadStarve  = "starve"
+	workloadRRWorst = "rr-worst"
+)
+
+// generatePoisson returns n processes with IDs 1..n whose arrivals form a
+// Poisson process of rate lambda (exponential inter-arrival times with mean
+// 1/lambda) and whose bursts are exponentially distributed with rate mu (mean
+// 1/mu). Arrivals are truncated and bursts rounded up to whole ticks, so every
+// burst is at least 1, and both saturate like toTicks. The same seed always
+// yields the same workload.
+func generatePoisson(lambda, mu float64, n int, seed int64) []Process {
+	rng := rand.New(rand.NewSource(seed))
+	processes := make([]Process, n)
+
+	var clock float64
+	for i := range processes {
+		clock += rng.ExpFloat64() / lambda
+		processes[i] = Process{
+			ProcessID:     int64(i + 1),
+			ArrivalTime:   toTicks(clock),
+			BurstDuration: toTicks(math.Max(1, math.Ceil(rng.ExpFloat64()/mu))),
+		}
+	}
+
+	return processes
+}
+
+// generateWorkload returns one of the known-hard workloads, with n processes
+// and IDs 1..n. Nothing is random, so a kind and size always give the same
+// workload:
+//   - convoy: a long job arrives first, then n-1 one-tick jobs queue behind
+//     it, each a tick apart.
+//   - starve: a long job arrives at 0 alongside a stream of two-tick jobs,
+//     one arriving every two ticks, so there is always a shorter job ready
+//     and shortest-job-first leaves the long job until last.
+//   - rr-worst: every job arrives at 0 needing one tick more than three
+//     quanta, so round-robin interleaves them all and finishes each with a
+//     one-tick slice, maximising context switches.
+func generateWorkload(kind string, n int, quantum int64) []Process {
+	processes := make([]Process, n)
+	for i := range processes {
+		p := Process{ProcessID: int64(i + 1)}
+		switch kind {
+		case workloadConvoy:
+			p.ArrivalTime, p.BurstDuration = int64(i), 1
+			if i == 0 {
+				p.BurstDuration = 10 * int64(n)
+			}
+		case workloadStarve:
+			p.ArrivalTime, p.BurstDuration = 2*int64(i-1), 2
+			if i == 0 {
+				p.ArrivalTime, p.BurstDuration = 0, 10
+			}
+		case workloadRRWorst:
+			p.BurstDuration = 3*quantum + 1
+		}
+		processes[i] = p
+	}
+
+	return processes
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func Test_generatePoisson(t *testing.T) {
+	t.Parallel()
+	first := generatePoisson(0.5, 0.25, 20, 42)
+	again := generatePoisson(0.5, 0.25, 20, 42)
+	if !reflect.DeepEqual(first, again) {
+		t.Fatalf("same seed generated different workloads:\n%v\n%v", first, again)
+	}
+	if other := generatePoisson(0.5, 0.25, 20, 43); reflect.DeepEqual(first, other) {
+		t.Error("different seeds generated identical workloads")
+	}
+
+	for i, p := range first {
+		if p.ProcessID != int64(i+1) {
+			t.Errorf("process %d has ID %d", i, p.ProcessID)
+		}
+		if p.BurstDuration < 1 {
+			t.Errorf("process %d has burst %d, want at least 1", p.ProcessID, p.BurstDuration)
+		}
+		if i > 0 && p.ArrivalTime < first[i-1].ArrivalTime {
+			t.Errorf("process %d arrives at %d, before its predecessor at %d", p.ProcessID, p.ArrivalTime, first[i-1].ArrivalTime)
+		}
+	}
+}
+
+func Test_generatePoisson_slowest(t *testing.T) {
+	t.Parallel()
+	processes := generatePoisson(1e-300, 1e-300, 3, 1)
+	for _, p := range processes {
+		if p.ArrivalTime != math.MaxInt64 || p.BurstDuration != math.MaxInt64 {
+			t.Errorf("process %d arrives at %d with burst %d, want both saturated at %d",
+				p.ProcessID, p.ArrivalTime, p.BurstDuration, int64(math.MaxInt64))
+		}
+	}
+	if err := checkHorizon(processes); !errors.Is(err, ErrInvalidRow) {
+		t.Errorf("checkHorizon() error = %v, want %v", err, ErrInvalidRow)
+	}
+
+	var w bytes.Buffer
+	err := run(&w, "binary_name", "-gen-poisson", "1e-10", "-n", "3")
+	if !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("run(-gen-poisson 1e-10) error = %v, want %v", err, ErrInvalidArgs)
+	}
+	err = run(&w, "binary_name", "-gen-poisson", "1", "-gen-service", "NaN", "-n", "3")
+	if !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("run(-gen-service NaN) error = %v, want %v", err, ErrInvalidArgs)
+	}
+	// Round-robin would cut bursts this long into millions of slices.
+	err = run(&w, "binary_name", "-algo", "fcfs", "-gen-poisson", "1e-9", "-gen-service", "1e-9", "-n", "3")
+	if err != nil {
+		t.Errorf("run() at the slowest rates error = %v", err)
+	}
+}
+
+func Test_generateWorkload(t *testing.T) {
+	t.Parallel()
+	if a, b := generateWorkload(workloadConvoy, 6, 4), generateWorkload(workloadConvoy, 6, 4); !reflect.DeepEqual(a, b) {
+		t.Fatalf("convoy workload is not deterministic:\n%v\n%v", a, b)
+	}
+
+	t.Run("convoy leads with the largest burst", func(t *testing.T) {
+		t.Parallel()
+		processes := generateWorkload(workloadConvoy, 6, 4)
+		for _, p := range processes[1:] {
+			if p.BurstDuration >= processes[0].BurstDuration {
+				t.Errorf("PID %d's burst %d is not below the first job's %d",
+					p.ProcessID, p.BurstDuration, processes[0].BurstDuration)
+			}
+		}
+	})
+
+	t.Run("starve leaves the long job for last under sjf", func(t *testing.T) {
+		t.Parallel()
+		sc, _ := lookupScheduler("sjf")
+		r := sc.schedule(generateWorkload(workloadStarve, 6, 4), defaultSettings())
+		if last := r.Gantt[len(r.Gantt)-1].PID; last != 1 {
+			t.Errorf("sjf ran PID %d last, want the long job, 1", last)
+		}
+	})
+
+	t.Run("rr-worst needs four slices per job", func(t *testing.T) {
+		t.Parallel()
+		sc, _ := lookupScheduler("rr")
+		r := sc.schedule(generateWorkload(workloadRRWorst, 3, defaultQuantum), defaultSettings())
+		if got, want := contextSwitches(r.Gantt), 4*3-1; got != want {
+			t.Errorf("rr made %d context switches, want %d", got, want)
+		}
+	})
+}
+
+func Test_generateFromDistributions_largest(t *testing.T) {
+	t.Parallel()
+	burst, err := parseDistribution("uniform:1e9,1e9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	arrival, err := parseDistribution("exponential:1e9")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	processes := generateFromDistributions(burst, &arrival, 100, 1)
+	for i, p := range processes {
+		if p.BurstDuration != 1e9 {
+			t.Errorf("process %d has burst %d, want 1e9", p.ProcessID, p.BurstDuration)
+		}
+		if p.ArrivalTime < 0 {
+			t.Errorf("process %d arrives at %d, before 0", p.ProcessID, p.ArrivalTime)
+		}
+		if i > 0 && p.ArrivalTime < processes[i-1].ArrivalTime {
+			t.Errorf("process %d arrives at %d, before its predecessor at %d",
+				p.ProcessID, p.ArrivalTime, processes[i-1].ArrivalTime)
+		}
+	}
+	if got := toTicks(1e300); got != math.MaxInt64 {
+		t.Errorf("toTicks(1e300) = %d, want %d", got, int64(math.MaxInt64))
+	}
+}
+
+func Test_generateFromDistributions(t *testing.T) {
+	t.Parallel()
+	burst, err := parseDistribution("normal:10,3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	arrival, err := parseDistribution("uniform:0,4")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := generateFromDistributions(burst, &arrival, 50, 7)
+	again := generateFromDistributions(burst, &arrival, 50, 7)
+	if !reflect.DeepEqual(first, again) {
+		t.Fatalf("same seed generated different workloads:\n%v\n%v", first, again)
+	}
+	if other := generateFromDistributions(burst, &arrival, 50, 8); reflect.DeepEqual(first, other) {
+		t.Error("different seeds generated identical workloads")
+	}
+
+	var total int64
+	for i, p := range first {
+		total += p.BurstDuration
+		if p.BurstDuration < 1 {
+			t.Errorf("process %d has burst %d, want at least 1", p.ProcessID, p.BurstDuration)
+		}
+		if i > 0 && p.ArrivalTime < first[i-1].ArrivalTime {
+			t.Errorf("process %d arrives at %d, before its predecessor at %d",
+				p.ProcessID, p.ArrivalTime, first[i-1].ArrivalTime)
+		}
+	}
+	// Rounding up adds about half a tick to the mean of 10.
+	if mean := float64(total) / float64(len(first)); mean < 9 || mean > 12 {
+		t.Errorf("mean burst %.2f is far from the normal's mean of 10", mean)
+	}
+
+	for _, spec := range []string{
+		"normal:10", "uniform:5,2", "exponential:0", "poisson:3", "normal:10,x", "uniform:1e300,1e300", "normal:10,Inf",
+	} {
+		if _, err := parseDistribution(spec); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("parseDistribution(%q) error = %v, want %v", spec, err, ErrInvalidArgs)
+		}
+	}
+}
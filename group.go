@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// loadGroupEvents reads a -group-events file: one <Group>,<Arrival Time>
+// record per named arrival event.
+func loadGroupEvents(r io.Reader) (map[string]int64, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = 2
+
+	events := make(map[string]int64)
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return events, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: group events row %d: %v", ErrInvalidRow, row, err)
+		}
+
+		group := strings.TrimSpace(record[0])
+		arrival, err := strconv.ParseInt(strings.TrimSpace(record[1]), 10, 64)
+		if err != nil || arrival < 0 {
+			return nil, fmt.Errorf("%w: group events row %d arrival %q is not a tick", ErrInvalidRow, row, record[1])
+		}
+		if _, ok := events[group]; ok {
+			return nil, fmt.Errorf("%w: group events row %d repeats group %q", ErrInvalidRow, row, group)
+		}
+		events[group] = arrival
+	}
+}
+
+// readGroupEvents loads the -group-events file at path.
+func readGroupEvents(path string) (map[string]int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%v: error opening group events file", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	return loadGroupEvents(f)
+}
+
+// applyGroupArrivals gives every process in a group its group's event
+// arrival in place of its own, so the whole group becomes ready at once. The
+// members are otherwise left in file order, and the schedulers break the tie
+// between them as they break any simultaneous arrival, so their order is the
+// same from run to run. Ungrouped processes keep their arrival; a group with
+// no event is an error, as it is most likely a typo.
+func applyGroupArrivals(processes []Process, events map[string]int64) ([]Process, error) {
+	grouped := make([]Process, len(processes))
+	for i, p := range processes {
+		if p.Group != "" {
+			arrival, ok := events[p.Group]
+			if !ok {
+				return nil, fmt.Errorf("%w: process %d is in group %q, which has no arrival event",
+					ErrInvalidRow, p.ProcessID, p.Group)
+			}
+			if p.Remaining > 0 && arrival != 0 {
+				return nil, fmt.Errorf("%w: process %d has a remaining burst but group %q arrives at %d, not 0",
+					ErrInvalidRow, p.ProcessID, p.Group, arrival)
+			}
+			p.ArrivalTime = arrival
+		}
+		grouped[i] = p
+	}
+
+	return grouped, nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_applyGroupArrivals(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	processesPath := filepath.Join(dir, "processes.csv")
+	eventsPath := filepath.Join(dir, "events.csv")
+	rows := "1,1,0,1,,,\n" +
+		"5,2,3,2,,,nightly\n" +
+		"3,2,7,2,,,nightly\n" +
+		"4,2,12,2,,,nightly\n"
+	if err := os.WriteFile(processesPath, []byte(rows), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(eventsPath, []byte("nightly,10\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s := defaultSettings()
+	s.groupEvents = eventsPath
+	processes, err := readProcesses(s, warner{w: io.Discard}, "binary_name", processesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	arrivals := make(map[int64]int64)
+	for _, p := range processes {
+		arrivals[p.ProcessID] = p.ArrivalTime
+	}
+	want := map[int64]int64{1: 0, 3: 10, 4: 10, 5: 10}
+	if !reflect.DeepEqual(arrivals, want) {
+		t.Errorf("arrivals = %v, want %v", arrivals, want)
+	}
+
+	// The group lands together on an idle CPU, so the order it is dispatched
+	// in shows how the tie was broken; it must not change between runs.
+	tests := []struct {
+		algorithm string
+		want      []int64
+	}{
+		{algorithm: "fcfs", want: []int64{5, 3, 4}},
+		{algorithm: "sjf", want: []int64{3, 4, 5}},
+		{algorithm: "rr", want: []int64{3, 4, 5}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.algorithm, func(t *testing.T) {
+			t.Parallel()
+			sc, _ := lookupScheduler(tt.algorithm)
+			for run := 0; run < 3; run++ {
+				var order []int64
+				for _, slice := range sc.schedule(processes, defaultSettings()).Gantt {
+					if slice.Start >= 10 {
+						order = append(order, slice.PID)
+					}
+				}
+				if !reflect.DeepEqual(order, tt.want) {
+					t.Fatalf("run %d dispatched the group as %v, want %v", run+1, order, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func Test_applyGroupArrivals_missingEvent(t *testing.T) {
+	t.Parallel()
+	events, err := loadGroupEvents(strings.NewReader("nightly,10\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = applyGroupArrivals([]Process{{ProcessID: 1, BurstDuration: 2, Group: "nighlty"}}, events)
+	if !errors.Is(err, ErrInvalidRow) {
+		t.Errorf("applyGroupArrivals() error = %v, want %v", err, ErrInvalidRow)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// resultHash returns a SHA-256 hex digest of r's canonical form, for pinning
+// a scheduler's behaviour on a workload. The canonical form is r as JSON
+// with its rows sorted by PID, its wait intervals by PID then start, and its
+// dropped PIDs in ascending order, so only what was scheduled matters and
+// not the order it was reported in. Presentation is left out: the title,
+// labels, -per-process summary and input line numbers.
+func resultHash(r ScheduleResult) (string, error) {
+	canonical := struct {
+		Name          string            `json:"name"`
+		Gantt         []TimeSlice       `json:"gantt"`
+		Rows          []ScheduleRow     `json:"rows"`
+		Metrics       Metrics           `json:"metrics"`
+		WaitIntervals []TimeSlice       `json:"waitIntervals"`
+		Predictions   map[int64]float64 `json:"predictions"`
+		Dropped       []int64           `json:"dropped"`
+	}{
+		Name:          r.Name,
+		Gantt:         r.Gantt,
+		Rows:          append([]ScheduleRow(nil), r.Rows...),
+		Metrics:       r.Metrics,
+		WaitIntervals: append([]TimeSlice(nil), r.WaitIntervals...),
+		Predictions:   r.Predictions,
+		Dropped:       append([]int64(nil), r.Dropped...),
+	}
+	for i := range canonical.Rows {
+		canonical.Rows[i].Line = 0
+	}
+	sort.SliceStable(canonical.Rows, func(i, j int) bool {
+		return canonical.Rows[i].ProcessID < canonical.Rows[j].ProcessID
+	})
+	sort.SliceStable(canonical.WaitIntervals, func(i, j int) bool {
+		a, b := canonical.WaitIntervals[i], canonical.WaitIntervals[j]
+		if a.PID != b.PID {
+			return a.PID < b.PID
+		}
+		return a.Start < b.Start
+	})
+	sort.Slice(canonical.Dropped, func(i, j int) bool { return canonical.Dropped[i] < canonical.Dropped[j] })
+
+	b, err := json.Marshal(canonical)
+	if err != nil {
+		return "", fmt.Errorf("%w: encoding %s result", err, r.Name)
+	}
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// outputHashes writes, in place of the report, each result's hash.
+func outputHashes(w io.Writer, results []ScheduleResult) error {
+	for i := range results {
+		hash, err := resultHash(results[i])
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(w, "%s: %s\n", results[i].Name, hash)
+	}
+
+	return nil
+}
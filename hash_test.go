@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+)
+
+func Test_resultHash(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 3, BurstDuration: 6, Priority: 3},
+	}
+	changed := append([]Process(nil), processes...)
+	changed[2].BurstDuration = 7
+
+	hashes := func(processes []Process) map[string]string {
+		hashes := make(map[string]string)
+		for _, r := range runSchedulers(processes, defaultSettings()) {
+			hash, err := resultHash(r)
+			if err != nil {
+				t.Fatalf("resultHash(%s) error = %v", r.Name, err)
+			}
+			hashes[r.Name] = hash
+		}
+		return hashes
+	}
+	first, again, other := hashes(processes), hashes(processes), hashes(changed)
+	for _, name := range defaultAlgorithms {
+		if first[name] != again[name] {
+			t.Errorf("%s: identical inputs hashed to %s and %s", name, first[name], again[name])
+		}
+		if first[name] == other[name] {
+			t.Errorf("%s: changing a burst left the hash at %s", name, first[name])
+		}
+	}
+
+	// Reporting order is not part of the hash.
+	r := runSchedulers(processes, defaultSettings())[0]
+	reordered := r
+	reordered.Rows = []ScheduleRow{r.Rows[2], r.Rows[0], r.Rows[1]}
+	reordered.Title = "renamed"
+	if got, _ := resultHash(reordered); got != first[r.Name] {
+		t.Errorf("reordered rows hashed to %s, want %s", got, first[r.Name])
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// HistogramBucket counts the processes whose turnaround falls in [Low, High].
+type HistogramBucket struct {
+	Low, High int64
+	Count     int
+}
+
+// turnaroundHistogram splits the range of the rows' turnarounds into n
+// buckets of equal whole-tick width, the last ending at the largest
+// turnaround, and counts the rows in each. Fewer buckets come back when the
+// range is narrower than n ticks.
+func turnaroundHistogram(rows []ScheduleRow, n int) []HistogramBucket {
+	if len(rows) == 0 || n < 1 {
+		return nil
+	}
+	low, high := rows[0].Turnaround, rows[0].Turnaround
+	for _, row := range rows {
+		if row.Turnaround < low {
+			low = row.Turnaround
+		}
+		if row.Turnaround > high {
+			high = row.Turnaround
+		}
+	}
+	// ceil((high-low+1) / n), worked out so that it cannot overflow.
+	width := (high-low)/int64(n) + 1
+
+	buckets := make([]HistogramBucket, 0, n)
+	for start := low; ; start += width {
+		if start > high-width {
+			buckets = append(buckets, HistogramBucket{Low: start, High: high})
+			break
+		}
+		buckets = append(buckets, HistogramBucket{Low: start, High: start + width - 1})
+	}
+	for _, row := range rows {
+		buckets[(row.Turnaround-low)/width].Count++
+	}
+
+	return buckets
+}
+
+// outputTurnaroundHistogram draws buckets as rows of one # per process.
+func outputTurnaroundHistogram(w io.Writer, buckets []HistogramBucket) {
+	_, _ = fmt.Fprintln(w, "Turnaround histogram")
+	labels := make([]string, len(buckets))
+	labelWidth := 0
+	for i, b := range buckets {
+		labels[i] = fmt.Sprintf("%d-%d", b.Low, b.High)
+		if len(labels[i]) > labelWidth {
+			labelWidth = len(labels[i])
+		}
+	}
+	for i, b := range buckets {
+		_, _ = fmt.Fprintf(w, "%*s | %s %d\n", labelWidth, labels[i], strings.Repeat("#", b.Count), b.Count)
+	}
+}
+
+// parseHistogramSpec parses a -turnaround-histogram value of the form
+// buckets=N.
+func parseHistogramSpec(v string) (int, error) {
+	key, value, ok := strings.Cut(v, "=")
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if !ok || strings.TrimSpace(key) != "buckets" || err != nil || n < 1 {
+		return 0, fmt.Errorf("%w: -turnaround-histogram %q, want buckets=N with N at least 1", ErrInvalidArgs, v)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func Test_turnaroundHistogram(t *testing.T) {
+	t.Parallel()
+	processes := generateWorkload(workloadStarve, 12, defaultQuantum)
+	for _, sc := range schedulers {
+		r := sc.schedule(processes, defaultSettings())
+		for _, n := range []int{1, 3, 5, 100} {
+			buckets := turnaroundHistogram(r.Rows, n)
+			total := 0
+			for _, b := range buckets {
+				total += b.Count
+			}
+			if total != len(processes) {
+				t.Errorf("%s with %d buckets: counts sum to %d, want %d", sc.name, n, total, len(processes))
+			}
+		}
+	}
+
+	rows := []ScheduleRow{{Turnaround: 2}, {Turnaround: 3}, {Turnaround: 9}, {Turnaround: 11}}
+	want := []HistogramBucket{{Low: 2, High: 5, Count: 2}, {Low: 6, High: 9, Count: 1}, {Low: 10, High: 11, Count: 1}}
+	if got := turnaroundHistogram(rows, 3); !reflect.DeepEqual(got, want) {
+		t.Errorf("turnaroundHistogram() = %v, want %v", got, want)
+	}
+
+	// Turnarounds spanning all of int64 must neither overflow the bucket
+	// width nor step past the last bucket.
+	rows = []ScheduleRow{{Turnaround: 0}, {Turnaround: math.MaxInt64 - 1}, {Turnaround: math.MaxInt64}}
+	for _, n := range []int{1, 2, 3} {
+		buckets := turnaroundHistogram(rows, n)
+		if len(buckets) != n || buckets[len(buckets)-1].High != math.MaxInt64 {
+			t.Errorf("turnaroundHistogram(%d buckets) over all of int64 = %v", n, buckets)
+		}
+	}
+
+	if _, err := parseHistogramSpec("5"); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("parseHistogramSpec(\"5\") error = %v, want %v", err, ErrInvalidArgs)
+	}
+}
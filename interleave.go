@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// outputInterleavedGantt stacks every result's Gantt chart on one time scale
+// running to the longest makespan, so the same instant falls in the same
+// column for every algorithm. Each tick is as many columns as the tightest
+// spot needs: a slice's label with a space either side, or a time under the
+// bar with a space before the next one. Idle time is left blank.
+func outputInterleavedGantt(w io.Writer, results []ScheduleResult) {
+	var (
+		makespan   int64
+		scale      int64 = 1
+		titleWidth int
+	)
+	for _, r := range results {
+		if width := runewidth.StringWidth(r.Title); width > titleWidth {
+			titleWidth = width
+		}
+		fit := func(ticks, columns int64) {
+			if perTick := (columns + ticks - 1) / ticks; perTick > scale {
+				scale = perTick
+			}
+		}
+		var last *TimeSlice
+		for i, slice := range r.Gantt {
+			if slice.Stop > makespan {
+				makespan = slice.Stop
+			}
+			ticks := slice.Stop - slice.Start
+			if ticks <= 0 {
+				continue
+			}
+			// The cell between the borders is ticks*scale-1 columns wide.
+			fit(ticks, int64(runewidth.StringWidth(ganttLabel(slice.PID, r.Labels)))+3)
+			fit(ticks, int64(len(fmt.Sprint(slice.Start)))+1)
+			if last != nil && slice.Start > last.Stop {
+				fit(slice.Start-last.Stop, int64(len(fmt.Sprint(last.Stop)))+1)
+			}
+			last = &r.Gantt[i]
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "Interleaved Gantt schedule (%d ticks, each %d wide)\n", makespan, scale)
+	margin := strings.Repeat(" ", titleWidth+1)
+	for _, r := range results {
+		var bar, axis strings.Builder
+		// barCol and axisCol are the next column to write on each line,
+		// counting from the time 0 border.
+		var barCol, axisCol int64
+		for _, slice := range r.Gantt {
+			left, right := slice.Start*scale, slice.Stop*scale
+			if right <= left {
+				continue
+			}
+			if barCol != left+1 {
+				bar.WriteString(strings.Repeat(" ", int(left-barCol)) + "|")
+			}
+			label := ganttLabel(slice.PID, r.Labels)
+			inner := int(right-left) - 1
+			pad := (inner - runewidth.StringWidth(label)) / 2
+			bar.WriteString(strings.Repeat(" ", pad) + label +
+				strings.Repeat(" ", inner-pad-runewidth.StringWidth(label)) + "|")
+			barCol = right + 1
+
+			for _, t := range []int64{slice.Start, slice.Stop} {
+				if at := t * scale; at >= axisCol {
+					axis.WriteString(strings.Repeat(" ", int(at-axisCol)) + fmt.Sprint(t))
+					axisCol = at + int64(len(fmt.Sprint(t))) + 1
+					axis.WriteString(" ")
+				}
+			}
+		}
+		_, _ = fmt.Fprintln(w, runewidth.FillRight(r.Title, titleWidth), strings.TrimRight(bar.String(), " "))
+		_, _ = fmt.Fprintln(w, strings.TrimRight(margin+axis.String(), " "))
+	}
+	_, _ = fmt.Fprintln(w)
+}
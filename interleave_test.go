@@ -0,0 +1,23 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func Test_outputInterleavedGantt(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+	}
+	s := defaultSettings()
+	s.algorithms = []string{"fcfs", "sjf"}
+
+	var w bytes.Buffer
+	outputInterleavedGantt(&w, runSchedulers(processes, s))
+	if got, want := w.String(), loadFixture(t, "interleave_test.txt"); got != want {
+		t.Errorf("outputInterleavedGantt() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -1,44 +1,241 @@
 package main
 
 import (
+	"bufio"
 	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
-	"sort"
+	"sync"
+	"time"
 
+	"github.com/mattn/go-runewidth"
 	"github.com/olekukonko/tablewriter"
 )
 
 func main() {
+	if err := runBuffered(os.Stdout, os.Args...); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// runBuffered is run with its output buffered on the way to w. The buffer is
+// flushed even when the run fails part-way, so whatever was rendered before
+// the error still appears.
+func runBuffered(w io.Writer, osArgs ...string) error {
+	out := bufio.NewWriter(w)
+	err := run(out, osArgs...)
+	if flushErr := out.Flush(); err == nil {
+		err = flushErr
+	}
+
+	return err
+}
+
+// run does everything main does, writing the report to w.
+func run(w io.Writer, osArgs ...string) error {
 	// CLI args
-	f, closeFile, err := openProcessingFile(os.Args...)
+	s, args, err := parseSettings(osArgs...)
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+	if len(args) == 3 && args[1] == "help" && args[2] == "algorithms" {
+		outputAlgorithmHelp(w)
+		return nil
 	}
-	defer closeFile()
+	if s.selfTest {
+		return selfTest(w, textbookCases)
+	}
+
+	warnings := s.warnings
+	if len(args) > 2 {
+		return runFiles(w, s, warnings, args[0], args[1:])
+	}
+
+	return report(w, s, warnings, args...)
+}
 
-	// Load and parse processes
-	processes, err := loadProcesses(f)
+// report loads the workload args name, schedules it and writes the report
+// to w.
+func report(w io.Writer, s settings, warnings warner, args ...string) error {
+	// Load and parse processes, or generate a workload
+	processes, err := readProcesses(s, warnings, args...)
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+	if allZeroBursts(processes) {
+		warnings.warn(warnZeroBurst, "every process has a zero burst, so nothing runs; throughput is reported as 0")
+	}
+	if s.dumpProcesses || s.dumpOnly {
+		if err := dumpProcesses(w, processes); err != nil {
+			return err
+		}
+		if s.dumpOnly {
+			return nil
+		}
+	}
+
+	if s.quantumFrac > 0 {
+		s.quantum = quantumFromFraction(processes, s.quantumFrac)
+		if s.format == formatText && !s.summaryTableOnly && !s.hash {
+			_, _ = fmt.Fprintf(w, "Round-robin quantum: %d (%g of mean burst %.2f)\n\n",
+				s.quantum, s.quantumFrac, meanBurst(processes))
+		}
+	}
+
+	// Run every scheduler, then render in the requested format
+	results := runSchedulers(processes, s)
+	for i := range results {
+		if s.validateSum {
+			if err := verifyFooterSums(results[i], s); err != nil {
+				return err
+			}
+		}
+		if s.strict {
+			if err := verifyNoOverlap(results[i]); err != nil {
+				return err
+			}
+			if s.noIdleAdvance {
+				if err := verifyNoIdle(results[i]); err != nil {
+					return err
+				}
+			}
+		}
+		if s.trace {
+			if err := writeTrace(s.stderr, results[i], s.traceFormat == traceJSON); err != nil {
+				return err
+			}
+		}
+	}
+	if s.statsFile != "" {
+		rows := statsRows(statsSource(s, args), results, s)
+		if s.pendingStats != nil {
+			*s.pendingStats = rows
+		} else if err := appendStats(s.statsFile, rows); err != nil {
+			return err
+		}
+	}
+	renderer, err := newRenderer(s)
+	if err != nil {
+		return err
+	}
+	if s.dryRender {
+		return dryRender(w, renderer, results)
+	}
+	if s.hash {
+		return outputHashes(w, results)
+	}
+	if s.summaryTableOnly {
+		outputComparison(w, results, s)
+		return nil
+	}
+	if s.interleaveOutput {
+		outputInterleavedGantt(w, results)
+	}
+	if err := renderAll(w, renderer, results); err != nil {
+		return err
+	}
+
+	// The analyses below only make sense alongside the text report.
+	if s.format != formatText {
+		return nil
+	}
+	if s.weights != nil {
+		outputRecommendation(w, results, s)
+	}
+	if s.sjfDelta {
+		if err := outputSJFDelta(w, processes, s); err != nil {
+			return err
+		}
 	}
+	if s.convoyAnalysis {
+		if err := outputConvoyAnalysis(w, processes, s); err != nil {
+			return err
+		}
+	}
+	if s.compareQuanta != nil {
+		outputQuantumComparison(w, compareQuanta(processes, s.compareQuanta, results, s), s)
+	}
+
+	return nil
+}
+
+// readProcesses returns the workload to schedule: a generated one when
+// requested, one queried from -db, otherwise the processes loaded from the
+// file named in args.
+func readProcesses(s settings, warnings warner, args ...string) ([]Process, error) {
+	var processes []Process
+	if s.genLambda > 0 || s.workload != "" || s.burstDist != nil {
+		if len(args) > 1 {
+			return nil, fmt.Errorf("%w: a generated workload takes no scheduling file", ErrInvalidArgs)
+		}
+		if s.workload != "" {
+			processes = generateWorkload(s.workload, s.genCount, s.quantum)
+		} else if s.burstDist != nil {
+			processes = generateFromDistributions(*s.burstDist, s.arrivalDist, s.genCount, s.genSeed)
+		} else {
+			processes = generatePoisson(s.genLambda, s.genMu, s.genCount, s.genSeed)
+		}
+		if err := checkHorizon(processes); err != nil {
+			return nil, err
+		}
+	} else if s.db != "" {
+		if len(args) > 1 {
+			return nil, fmt.Errorf("%w: -db takes no scheduling file", ErrInvalidArgs)
+		}
+		db, err := openDB(s.db)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = db.Close() }()
 
-	// First-come, first-serve scheduling
-	FCFSSchedule(os.Stdout, "First-come, first-serve", processes)
+		if processes, err = loadProcessesSQL(db, s); err != nil {
+			return nil, err
+		}
+	} else {
+		f, closeFile, err := openProcessingFile(args...)
+		if err != nil {
+			return nil, err
+		}
+		defer closeFile()
+
+		if processes, err = loadProcesses(f, s, warnings.of(warnSkippedRow)); err != nil {
+			return nil, err
+		}
+		if s.checkMonotonicIDs {
+			if err := checkMonotonicIDs(processes); err != nil {
+				return nil, err
+			}
+		}
+		if s.groupEvents != "" {
+			events, err := readGroupEvents(s.groupEvents)
+			if err != nil {
+				return nil, err
+			}
+			if processes, err = applyGroupArrivals(processes, events); err != nil {
+				return nil, err
+			}
+		}
+	}
 
-	// Shortest-job-first scheduling
-	SJFSchedule(os.Stdout, "Shortest-job-first", processes)
+	if s.onlyIDs != nil {
+		var err error
+		if processes, err = filterIDs(processes, s.onlyIDs); err != nil {
+			return nil, err
+		}
+	}
+	if s.arrivalOffset != 0 {
+		return offsetArrivals(processes, s.arrivalOffset)
+	}
 
-	// Shortest-job-first priority scheduling 
-	SJFPrioritySchedule(os.Stdout, "Priority", processes)
-	
-	// Robin-round scheduling
-	RRSchedule(os.Stdout, "Round-robin", processes)
+	return processes, nil
 }
 
 func openProcessingFile(args ...string) (*os.File, func(), error) {
@@ -64,15 +261,245 @@ type (
 		ProcessID     int64
 		ArrivalTime   int64
 		BurstDuration int64
-		Priority      int64
+		// Priority ranks processes for the schedulers that use it: lower is
+		// more important, and negative values are allowed, so -1 outranks 0.
+		Priority int64
+		// NoPriority is set when the input row gave no priority at all, so
+		// Priority's 0 is a default rather than a choice.
+		NoPriority bool
+		Label      string
+		// Remaining, when positive, is how much of the burst is left for a
+		// process already part-way through it at t=0; schedulers only need
+		// to run that much.
+		Remaining int64
+		// Group names the batch arrival event the process belongs to, if
+		// any; with -group-events it arrives when the event does.
+		Group string
+		// Line is the input line the process was read from, or 0 when it
+		// did not come from a file.
+		Line int
 	}
 	TimeSlice struct {
-		PID   int64
-		Start int64
-		Stop  int64
+		PID   int64 `json:"pid"`
+		Start int64 `json:"start"`
+		Stop  int64 `json:"stop"`
+	}
+	// ScheduleRow is one process's line in the schedule table.
+	ScheduleRow struct {
+		ProcessID  int64 `json:"pid"`
+		Line       int   `json:"line,omitempty"`
+		Priority   int64 `json:"priority"`
+		NoPriority bool  `json:"noPriority,omitempty"`
+		Burst      int64 `json:"burst"`
+		Arrival    int64 `json:"arrival"`
+		// FirstStart is when the process was first dispatched, which for
+		// preemptive schedulers can be well before it completes. It is nil
+		// for a process that -max-time stopped before it ever ran.
+		FirstStart *int64 `json:"firstStart,omitempty"`
+		Wait       int64  `json:"wait"`
+		// ContentionWait counts only the time spent waiting while another
+		// process held the CPU, leaving out waits caused by the CPU idling.
+		ContentionWait int64 `json:"contentionWait"`
+		Turnaround     int64 `json:"turnaround"`
+		Exit           int64 `json:"exit"`
+		// Unfinished marks a process still running when -max-time stopped
+		// the run; its Exit is meaningless and its Wait and Turnaround are
+		// measured up to the cap.
+		Unfinished bool `json:"unfinished,omitempty"`
+	}
+	// Metrics are the averages reported in the schedule table footer.
+	Metrics struct {
+		AverageWait           float64 `json:"averageWait"`
+		AverageContentionWait float64 `json:"averageContentionWait"`
+		AverageTurnaround     float64 `json:"averageTurnaround"`
+		Throughput            float64 `json:"throughput"`
+		// AverageQueueLength is the time-average number of processes in
+		// the ready queue: the total time every process spent waiting,
+		// divided by the makespan.
+		AverageQueueLength float64 `json:"averageQueueLength"`
+	}
+	// ScheduleResult is everything a scheduler produces for one run, ready to
+	// be rendered in any output format.
+	ScheduleResult struct {
+		Name    string        `json:"name"`
+		Title   string        `json:"title"`
+		Gantt   []TimeSlice   `json:"gantt"`
+		Rows    []ScheduleRow `json:"rows"`
+		Metrics Metrics       `json:"metrics"`
+		// WaitIntervals holds the spans each process sat in the ready queue,
+		// for schedulers that track them.
+		WaitIntervals []TimeSlice `json:"waitIntervals,omitempty"`
+		// Predictions holds the burst each process was predicted to need
+		// when it was dispatched, for schedulers that predict bursts.
+		Predictions map[int64]float64 `json:"predictions,omitempty"`
+		// Dropped lists the processes turned away because the ready queue
+		// was full, for schedulers that honour -queue-cap.
+		Dropped []int64 `json:"dropped,omitempty"`
+		// Summary holds the -per-process table, when requested.
+		Summary []ProcessSummary `json:"summary,omitempty"`
+		// Labels maps each labelled process to its label, for -gantt-labels.
+		Labels map[int64]string `json:"labels,omitempty"`
 	}
 )
 
+// scheduler is a registered scheduling algorithm. description and params
+// feed "help algorithms".
+type scheduler struct {
+	name        string
+	title       string
+	description string
+	params      []string
+	run         func(processes []Process, s settings) ScheduleResult
+}
+
+// schedulers lists every algorithm the CLI can run.
+var schedulers = []scheduler{
+	{
+		name: "fcfs", title: "First-come, first-serve", run: fcfsSchedule,
+		description: "runs processes to completion in file order",
+		params:      []string{"-queue-cap", "-overflow"},
+	},
+	{
+		name: "sjf", title: "Shortest-job-first", run: sjfSchedule,
+		description: "runs processes to completion, shortest burst first",
+	},
+	{
+		name: "priority", title: "Priority", run: sjfPrioritySchedule,
+		description: "shortest-job-first with equal bursts ordered by priority (lower first)",
+	},
+	{
+		name: "rr", title: "Round-robin", run: roundRobinSchedule,
+		description: "time-slices the ready queue in arrival order, with a quantum of 4 ticks by default",
+		params: []string{
+			"-quantum-frac", "-round-robin-preempt-on-arrival", "-rr-requeue-order", "-show-waiting-intervals",
+			"-queue-cap", "-overflow",
+		},
+	},
+	{
+		name: "spn", title: "Shortest-process-next (predicted)", run: spnSchedule,
+		description: "runs the ready process with the smallest predicted burst, learning per label",
+		params:      []string{"-spn-alpha", "-spn-tau0"},
+	},
+}
+
+// defaultAlgorithms are the schedulers run, in output order, when -algo is
+// not given.
+var defaultAlgorithms = []string{"fcfs", "sjf", "priority", "rr"}
+
+// lookupScheduler finds a registered scheduler by name.
+func lookupScheduler(name string) (scheduler, bool) {
+	for i := range schedulers {
+		if schedulers[i].name == name {
+			return schedulers[i], true
+		}
+	}
+
+	return scheduler{}, false
+}
+
+// schedule runs the algorithm and fills in what every result shares: its
+// name, title and the columns derived from the Gantt chart.
+func (sc scheduler) schedule(processes []Process, s settings) ScheduleResult {
+	arrivals := make(map[int64]int64, len(processes))
+	lines := make(map[int64]int, len(processes))
+	noPriority := make(map[int64]bool, len(processes))
+	for i := range processes {
+		arrivals[processes[i].ProcessID] = processes[i].ArrivalTime
+		lines[processes[i].ProcessID] = processes[i].Line
+		noPriority[processes[i].ProcessID] = processes[i].NoPriority
+	}
+
+	// Schedulers may reorder what they are given, so each gets its own copy
+	// and the caller's slice is safe to share between runs.
+	processes = resumePartial(processes)
+	r := sc.run(append([]Process(nil), processes...), s)
+	r.Name = sc.name
+	r.Title = sc.title
+	if s.maxTime > 0 {
+		capSchedule(&r, withoutPIDs(processes, r.Dropped), s.maxTime, s.avgCompletedOnly)
+	}
+	addContentionWait(&r, arrivals, s.maxTime)
+	for i := range r.Rows {
+		r.Rows[i].FirstStart = firstStart(r.Gantt, r.Rows[i].ProcessID)
+		r.Rows[i].Line = lines[r.Rows[i].ProcessID]
+		r.Rows[i].NoPriority = noPriority[r.Rows[i].ProcessID]
+	}
+	if s.perProcess {
+		r.Summary = summarizeProcesses(r, processes, s.maxTime)
+	}
+	if s.ganttLabels {
+		r.Labels = processLabels(processes)
+	}
+
+	return r
+}
+
+// processLabels maps each process that has a label to it.
+func processLabels(processes []Process) map[int64]string {
+	labels := make(map[int64]string)
+	for i := range processes {
+		if processes[i].Label != "" {
+			labels[processes[i].ProcessID] = processes[i].Label
+		}
+	}
+
+	return labels
+}
+
+// resumePartial returns processes with the burst of each already-running
+// process cut down to what it has remaining, leaving processes untouched.
+func resumePartial(processes []Process) []Process {
+	var resumed []Process
+	for i := range processes {
+		if processes[i].Remaining <= 0 {
+			continue
+		}
+		if resumed == nil {
+			resumed = append([]Process(nil), processes...)
+		}
+		resumed[i].BurstDuration = processes[i].Remaining
+		resumed[i].Remaining = 0
+	}
+	if resumed == nil {
+		return processes
+	}
+
+	return resumed
+}
+
+// runSchedulers runs the selected schedulers over processes, returning their
+// results in order. With s.parallel each scheduler runs on its own goroutine.
+func runSchedulers(processes []Process, s settings) []ScheduleResult {
+	names := s.algorithms
+	if names == nil {
+		names = defaultAlgorithms
+	}
+
+	chosen := make([]scheduler, 0, len(names))
+	for _, name := range names {
+		if sc, ok := lookupScheduler(name); ok {
+			chosen = append(chosen, sc)
+		}
+	}
+
+	results := make([]ScheduleResult, len(chosen))
+	var wg sync.WaitGroup
+	for i := range chosen {
+		if !s.parallel {
+			results[i] = chosen[i].schedule(processes, s)
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = chosen[i].schedule(processes, s)
+		}(i)
+	}
+	wg.Wait()
+
+	return results
+}
+
 //region Schedulers
 
 // FCFSSchedule outputs a schedule of processes in a GANTT chart and a table of timing given:
@@ -80,38 +507,66 @@ type (
 // • a title for the chart
 // • a slice of processes
 func FCFSSchedule(w io.Writer, title string, processes []Process) {
+	r := scheduler{title: title, run: fcfsSchedule}.schedule(processes, defaultSettings())
+	outputResult(w, r, defaultSettings())
+}
+
+// SJFSchedule outputs a shortest-job-first schedule; see FCFSSchedule.
+func SJFSchedule(w io.Writer, title string, processes []Process) {
+	r := scheduler{title: title, run: sjfSchedule}.schedule(processes, defaultSettings())
+	outputResult(w, r, defaultSettings())
+}
+
+// SJFPrioritySchedule outputs a shortest-job-first schedule with ties broken
+// by priority; see FCFSSchedule.
+func SJFPrioritySchedule(w io.Writer, title string, processes []Process) {
+	r := scheduler{title: title, run: sjfPrioritySchedule}.schedule(processes, defaultSettings())
+	outputResult(w, r, defaultSettings())
+}
+
+// RRSchedule outputs a round-robin schedule using the default settings.
+func RRSchedule(w io.Writer, title string, processes []Process) {
+	r := scheduler{title: title, run: roundRobinSchedule}.schedule(processes, defaultSettings())
+	outputResult(w, r, defaultSettings())
+}
+
+// fcfsSchedule runs processes in file order. With s.queueCap set, an arrival
+// finding the ready queue full is dropped under the drop policy. Under the
+// delay policy it waits outside the queue until there is room, but as
+// dispatch order is fixed by the file, that never changes the schedule.
+func fcfsSchedule(processes []Process, s settings) ScheduleResult {
 	var (
-		serviceTime     int64
-		totalWait       float64
-		totalTurnaround float64
-		lastCompletion  float64
-		waitingTime     int64
-		schedule        = make([][]string, len(processes))
-		gantt           = make([]TimeSlice, 0)
+		serviceTime int64
+		waitingTime int64
+		schedule    = make([]ScheduleRow, 0, len(processes))
+		gantt       = make([]TimeSlice, 0)
+		queued      []TimeSlice
+		dropped     []int64
 	)
 	for i := range processes {
-		if processes[i].ArrivalTime > 0 {
-			waitingTime = serviceTime - processes[i].ArrivalTime
+		if s.queueCap > 0 && s.overflow == overflowDrop &&
+			queuedAt(queued, processes[i].ArrivalTime) >= s.queueCap {
+			dropped = append(dropped, processes[i].ProcessID)
+			continue
 		}
-		totalWait += float64(waitingTime)
-
+		// An arrival after the CPU went idle starts on arrival.
+		if processes[i].ArrivalTime > serviceTime {
+			serviceTime = processes[i].ArrivalTime
+		}
+		waitingTime = serviceTime - processes[i].ArrivalTime
 		start := waitingTime + processes[i].ArrivalTime
 
 		turnaround := processes[i].BurstDuration + waitingTime
-		totalTurnaround += float64(turnaround)
-
 		completion := processes[i].BurstDuration + processes[i].ArrivalTime + waitingTime
-		lastCompletion = float64(completion)
-
-		schedule[i] = []string{
-			fmt.Sprint(processes[i].ProcessID),
-			fmt.Sprint(processes[i].Priority),
-			fmt.Sprint(processes[i].BurstDuration),
-			fmt.Sprint(processes[i].ArrivalTime),
-			fmt.Sprint(waitingTime),
-			fmt.Sprint(turnaround),
-			fmt.Sprint(completion),
-		}
+		schedule = append(schedule, ScheduleRow{
+			ProcessID:  processes[i].ProcessID,
+			Priority:   processes[i].Priority,
+			Burst:      processes[i].BurstDuration,
+			Arrival:    processes[i].ArrivalTime,
+			Wait:       waitingTime,
+			Turnaround: turnaround,
+			Exit:       completion,
+		})
 		serviceTime += processes[i].BurstDuration
 
 		gantt = append(gantt, TimeSlice{
@@ -119,171 +574,230 @@ func FCFSSchedule(w io.Writer, title string, processes []Process) {
 			Start: start,
 			Stop:  serviceTime,
 		})
+		queued = append(queued, TimeSlice{
+			PID:   processes[i].ProcessID,
+			Start: processes[i].ArrivalTime,
+			Stop:  start,
+		})
+	}
+
+	return ScheduleResult{
+		Gantt:   gantt,
+		Rows:    schedule,
+		Metrics: computeMetrics(withoutPIDs(processes, dropped), gantt),
+		Dropped: dropped,
 	}
+}
 
-	count := float64(len(processes))
-	aveWait := totalWait / count
-	aveTurnaround := totalTurnaround / count
-	aveThroughput := count / lastCompletion
+// queuedAt counts the spans, each from a process's arrival to its dispatch,
+// that are still open at t: how many processes sat in the ready queue then.
+func queuedAt(queued []TimeSlice, t int64) int {
+	n := 0
+	for i := range queued {
+		if queued[i].Start <= t && t < queued[i].Stop {
+			n++
+		}
+	}
 
-	outputTitle(w, title)
-	outputGantt(w, gantt)
-	outputSchedule(w, schedule, aveWait, aveTurnaround, aveThroughput)
+	return n
 }
 
-func SJFSchedule(w io.Writer, title string, processes []Process) {
-    var (
-        currentTime    int64
-        totalWait      float64
-        totalTurnaround float64
-        lastCompletion float64
-        waitingTime    int64
-        schedule       = make([][]string, len(processes))
-        gantt          = make([]TimeSlice, 0)
-        remaining      = make([]Process, len(processes))
-    )
-
-    copy(remaining, processes)
-    sort.Slice(remaining, func(i, j int) bool {
-        return remaining[i].BurstDuration < remaining[j].BurstDuration
-    })
-
-    for len(remaining) > 0 {
-        current := remaining[0]
-        remaining = remaining[1:]
-
-        if current.ArrivalTime > currentTime {
-            waitingTime = current.ArrivalTime - currentTime
-        } else {
-            waitingTime = 0
-        }
-        totalWait += float64(waitingTime)
-
-        start := currentTime + waitingTime
-
-        turnaround := current.BurstDuration + waitingTime
-        totalTurnaround += float64(turnaround)
-
-        completion := current.BurstDuration + start
-        lastCompletion = float64(completion)
-
-        schedule[current.ProcessID-1] = []string{
-            fmt.Sprint(current.ProcessID),
-            fmt.Sprint(current.Priority),
-            fmt.Sprint(current.BurstDuration),
-            fmt.Sprint(current.ArrivalTime),
-            fmt.Sprint(waitingTime),
-            fmt.Sprint(turnaround),
-            fmt.Sprint(completion),
-        }
-        currentTime = completion
-
-        gantt = append(gantt, TimeSlice{
-            PID:   current.ProcessID,
-            Start: start,
-            Stop:  completion,
-        })
-    }
-
-    count := float64(len(processes))
-    aveWait := totalWait / count
-    aveTurnaround := totalTurnaround / count
-    aveThroughput := count / lastCompletion
-
-    outputTitle(w, title)
-    outputGantt(w, gantt)
-    outputSchedule(w, schedule, aveWait, aveTurnaround, aveThroughput)
-}
-
-
-func SJFPrioritySchedule(w io.Writer, title string, processes []Process) { 
+// withoutPIDs returns processes minus those whose IDs are listed.
+func withoutPIDs(processes []Process, pids []int64) []Process {
+	if len(pids) == 0 {
+		return processes
+	}
+	skip := make(map[int64]bool, len(pids))
+	for _, pid := range pids {
+		skip[pid] = true
+	}
+	kept := make([]Process, 0, len(processes))
+	for _, p := range processes {
+		if !skip[p.ProcessID] {
+			kept = append(kept, p)
+		}
+	}
+
+	return kept
+}
+
+func sjfSchedule(processes []Process, s settings) ScheduleResult {
+	var (
+		currentTime int64
+		waitingTime int64
+		schedule    = make([]ScheduleRow, len(processes))
+		gantt       = make([]TimeSlice, 0)
+		remaining   = make([]Process, len(processes))
+	)
+
+	// Rows stay in input order, whatever the IDs are.
+	rowIndex := make(map[int64]int, len(processes))
+	for i := range processes {
+		rowIndex[processes[i].ProcessID] = i
+	}
+
+	copy(remaining, processes)
+	ties := s.tieBreaker(processes)
+	sort.Slice(remaining, func(i, j int) bool {
+		if remaining[i].BurstDuration != remaining[j].BurstDuration {
+			return remaining[i].BurstDuration < remaining[j].BurstDuration
+		}
+		return ties(remaining[i], remaining[j])
+	})
+
+	for len(remaining) > 0 {
+		current := remaining[0]
+		remaining = remaining[1:]
+
+		start := currentTime
+		if current.ArrivalTime > start {
+			start = current.ArrivalTime
+		}
+		waitingTime = start - current.ArrivalTime
+
+		turnaround := current.BurstDuration + waitingTime
+		completion := current.BurstDuration + start
+		schedule[rowIndex[current.ProcessID]] = ScheduleRow{
+			ProcessID:  current.ProcessID,
+			Priority:   current.Priority,
+			Burst:      current.BurstDuration,
+			Arrival:    current.ArrivalTime,
+			Wait:       waitingTime,
+			Turnaround: turnaround,
+			Exit:       completion,
+		}
+		currentTime = completion
+
+		gantt = append(gantt, TimeSlice{
+			PID:   current.ProcessID,
+			Start: start,
+			Stop:  completion,
+		})
+	}
+
+	return ScheduleResult{
+		Gantt:   gantt,
+		Rows:    schedule,
+		Metrics: computeMetrics(processes, gantt),
+	}
+}
+
+func sjfPrioritySchedule(processes []Process, s settings) ScheduleResult {
 	var (
-		serviceTime		int64
-		totalWait		float64
-		totalTurnaround float64
-		lastCompletion	float64
-		waitingTime		int64
-		schedule		= make([][]string, len(processes))
-		gantt			= make([]TimeSlice, 0)	
+		serviceTime int64
+		waitingTime int64
+		schedule    = make([]ScheduleRow, len(processes))
+		gantt       = make([]TimeSlice, 0)
 	)
 
+	// Burst, then priority, then arrival, then the tie-break strategy (PID by
+	// default), so fully tied jobs are still dispatched in a deterministic order.
+	ties := s.tieBreaker(processes)
 	sort.Slice(processes, func(i, j int) bool {
-		if processes[i].BurstDuration == processes[j].BurstDuration {
+		if processes[i].BurstDuration != processes[j].BurstDuration {
+			return processes[i].BurstDuration < processes[j].BurstDuration
+		}
+		if processes[i].Priority != processes[j].Priority {
 			return processes[i].Priority < processes[j].Priority
 		}
-		return processes[i].BurstDuration < processes[j].BurstDuration
+		if processes[i].ArrivalTime != processes[j].ArrivalTime {
+			return processes[i].ArrivalTime < processes[j].ArrivalTime
+		}
+		return ties(processes[i], processes[j])
 	})
 
-	for i:= range processes {
+	for i := range processes {
 		if processes[i].ArrivalTime > serviceTime {
 			waitingTime = 0
 			serviceTime = processes[i].ArrivalTime
 		} else {
 			waitingTime = serviceTime - processes[i].ArrivalTime
 		}
-		totalWait += float64(waitingTime)
-
 		start := serviceTime
 
 		turnaround := processes[i].BurstDuration + waitingTime
-		totalTurnaround += float64(turnaround)
-
 		completion := start + processes[i].BurstDuration
-		lastCompletion = float64(completion)
-		
-		schedule[i] = []string{
-			fmt.Sprint(processes[i].ProcessID),
-			fmt.Sprint(processes[i].Priority),
-			fmt.Sprint(processes[i].BurstDuration),
-			fmt.Sprint(processes[i].ArrivalTime),
-			fmt.Sprint(waitingTime),
-			fmt.Sprint(turnaround),
-			fmt.Sprint(completion),
+		schedule[i] = ScheduleRow{
+			ProcessID:  processes[i].ProcessID,
+			Priority:   processes[i].Priority,
+			Burst:      processes[i].BurstDuration,
+			Arrival:    processes[i].ArrivalTime,
+			Wait:       waitingTime,
+			Turnaround: turnaround,
+			Exit:       completion,
 		}
 		serviceTime += processes[i].BurstDuration
 		gantt = append(gantt, TimeSlice{
-			PID: processes[i].ProcessID,
+			PID:   processes[i].ProcessID,
 			Start: start,
-			Stop: serviceTime,
+			Stop:  serviceTime,
 		})
 	}
 
-	count := float64(len(processes))
-	aveWait := totalWait / count
-	aveTurnaround := totalTurnaround / count
-	aveThroughput := count / lastCompletion
-
-	outputTitle(w, title)
-	outputGantt(w, gantt)
-	outputSchedule(w, schedule, aveWait, aveTurnaround, aveThroughput)
+	return ScheduleResult{
+		Gantt:   gantt,
+		Rows:    schedule,
+		Metrics: computeMetrics(processes, gantt),
+	}
 }
 
-
-const quantum int64 = 4 
-
-func RRSchedule(w io.Writer, title string, processes []Process) {
+// roundRobinSchedule runs round-robin with the quantum from s. When
+// s.preemptOnArrival is set, a job arriving with a higher priority (lower
+// number) than the running job cuts the running quantum short: the arrival is
+// dispatched next and the preempted job goes to the tail of the ready queue
+// with its remaining burst. The unused part of the quantum is forfeited; the
+// preempted job gets a fresh full quantum when it is next dispatched.
+//
+// With s.queueCap set, an arrival finding the ready queue full (the running
+// job aside) is dropped, or under the delay policy held back, along with
+// everything arriving after it, until the queue has room.
+//
+// A job whose quantum runs out rejoins the queue ahead of the jobs admitted
+// at that same instant. Under s.rrRequeueOrder remaining, that batch is
+// instead ordered by remaining burst, shortest first, keeping that order for
+// equal bursts.
+func roundRobinSchedule(processes []Process, s settings) ScheduleResult {
 	var (
-		currentTime     int64
-		totalWait       float64
-		totalTurnaround float64
-		lastCompletion  float64
-		waitingTime     = make(map[int64]int64) 
-		schedule        = make([][]string, 0)
-		gantt           = make([]TimeSlice, 0)
-		queue           []Process
+		currentTime   int64
+		original      = make(map[int64]Process, len(processes))
+		schedule      = make([]ScheduleRow, 0)
+		gantt         = make([]TimeSlice, 0)
+		waitIntervals = make([]TimeSlice, 0)
+		queue         []Process
+		dropped       []int64
+		all           = processes
+		// batchStart is where in queue the processes that joined it at
+		// the current instant begin: the one just requeued, then arrivals.
+		batchStart int
 	)
 
+	for _, p := range processes {
+		original[p.ProcessID] = p
+	}
+	ties := s.tieBreaker(processes)
 	sort.Slice(processes, func(i, j int) bool {
-		return processes[i].ArrivalTime < processes[j].ArrivalTime
+		if processes[i].ArrivalTime != processes[j].ArrivalTime {
+			return processes[i].ArrivalTime < processes[j].ArrivalTime
+		}
+		return ties(processes[i], processes[j])
 	})
 
 	for len(processes) > 0 || len(queue) > 0 {
-		for i, p := range processes {
-			if p.ArrivalTime <= currentTime {
-				queue = append(queue, p)
-				processes = append(processes[:i], processes[i+1:]...) 
-				i-- 
+		for len(processes) > 0 && processes[0].ArrivalTime <= currentTime {
+			if s.queueCap > 0 && len(queue) >= s.queueCap {
+				if s.overflow == overflowDelay {
+					break
+				}
+				dropped = append(dropped, processes[0].ProcessID)
+				processes = processes[1:]
+				continue
 			}
+			queue = append(queue, processes[0])
+			processes = processes[1:]
+		}
+		if s.rrRequeueOrder == requeueRemaining {
+			batch := queue[batchStart:]
+			sort.SliceStable(batch, func(i, j int) bool { return batch[i].BurstDuration < batch[j].BurstDuration })
 		}
 
 		if len(queue) == 0 {
@@ -294,33 +808,52 @@ func RRSchedule(w io.Writer, title string, processes []Process) {
 		currentProcess := queue[0]
 		queue = queue[1:]
 
-		execTime := quantum
-		if currentProcess.BurstDuration < quantum {
+		execTime := s.quantum
+		if currentProcess.BurstDuration < s.quantum {
 			execTime = currentProcess.BurstDuration
 		}
 
-		waitingTime[currentProcess.ProcessID] = currentTime - currentProcess.ArrivalTime
-		totalWait += float64(waitingTime[currentProcess.ProcessID])
+		preemptor := -1
+		if s.preemptOnArrival {
+			preemptor = preemptingArrival(processes, currentProcess, currentTime, currentTime+execTime)
+			if preemptor >= 0 {
+				execTime = processes[preemptor].ArrivalTime - currentTime
+			}
+		}
+
+		if currentTime > currentProcess.ArrivalTime {
+			waitIntervals = append(waitIntervals, TimeSlice{
+				PID:   currentProcess.ProcessID,
+				Start: currentProcess.ArrivalTime,
+				Stop:  currentTime,
+			})
+		}
 
 		currentTime += execTime
 		currentProcess.BurstDuration -= execTime
 
+		if preemptor >= 0 {
+			queue = append([]Process{processes[preemptor]}, queue...)
+			processes = append(processes[:preemptor:preemptor], processes[preemptor+1:]...)
+		}
+
+		batchStart = len(queue)
 		if currentProcess.BurstDuration > 0 {
-			currentProcess.ArrivalTime = currentTime 
+			currentProcess.ArrivalTime = currentTime
 			queue = append(queue, currentProcess)
 		} else {
-			turnaround := currentTime - currentProcess.ArrivalTime + waitingTime[currentProcess.ProcessID]
-			totalTurnaround += float64(turnaround)
-			lastCompletion = float64(currentTime)
-
-			schedule = append(schedule, []string{
-				fmt.Sprint(currentProcess.ProcessID),
-				fmt.Sprint(currentProcess.Priority),
-				fmt.Sprint(currentProcess.BurstDuration + execTime),
-				fmt.Sprint(currentProcess.ArrivalTime - waitingTime[currentProcess.ProcessID]),
-				fmt.Sprint(waitingTime[currentProcess.ProcessID]),
-				fmt.Sprint(turnaround),
-				fmt.Sprint(currentTime),
+			// Rows describe the whole run, not the final quantum.
+			p := original[currentProcess.ProcessID]
+			turnaround := currentTime - p.ArrivalTime
+
+			schedule = append(schedule, ScheduleRow{
+				ProcessID:  currentProcess.ProcessID,
+				Priority:   currentProcess.Priority,
+				Burst:      p.BurstDuration,
+				Arrival:    p.ArrivalTime,
+				Wait:       turnaround - p.BurstDuration,
+				Turnaround: turnaround,
+				Exit:       currentTime,
 			})
 		}
 
@@ -331,89 +864,1055 @@ func RRSchedule(w io.Writer, title string, processes []Process) {
 		})
 	}
 
-	aveWait := totalWait / float64(len(schedule))
-	aveTurnaround := totalTurnaround / float64(len(schedule))
-	aveThroughput := float64(len(schedule)) / lastCompletion
-
-	outputTitle(w, title)
-	outputGantt(w, gantt)
-	outputSchedule(w, schedule, aveWait, aveTurnaround, aveThroughput)
+	return ScheduleResult{
+		Gantt:         gantt,
+		Rows:          schedule,
+		WaitIntervals: waitIntervals,
+		Metrics:       computeMetrics(withoutPIDs(all, dropped), gantt),
+		Dropped:       dropped,
+	}
 }
 
+// preemptingArrival returns the index in pending (sorted by arrival) of the
+// first job arriving strictly inside (from, to) with a higher priority than
+// running, or -1 when the running job keeps the CPU for its whole quantum.
+func preemptingArrival(pending []Process, running Process, from, to int64) int {
+	for i := range pending {
+		if pending[i].ArrivalTime >= to {
+			break
+		}
+		if pending[i].ArrivalTime > from && pending[i].Priority < running.Priority {
+			return i
+		}
+	}
+
+	return -1
+}
 
 //endregion
 
-//region Output helpers
+//region Schedule analysis
 
-func outputTitle(w io.Writer, title string) {
-	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
-	_, _ = fmt.Fprintln(w, strings.Repeat(" ", len(title)/2), title)
-	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+// longestIdleGap returns the start and length of the longest contiguous
+// interval in which the CPU sat idle, measured between Gantt slices (and from
+// t=0 up to the first slice). Ties go to the earliest gap; a length of 0 means
+// the CPU never idled.
+func longestIdleGap(gantt []TimeSlice) (start, length int64) {
+	var lastStop int64
+	for i := range gantt {
+		if gap := gantt[i].Start - lastStop; gap > length {
+			start, length = lastStop, gap
+		}
+		if gantt[i].Stop > lastStop {
+			lastStop = gantt[i].Stop
+		}
+	}
+
+	return start, length
 }
 
-func outputGantt(w io.Writer, gantt []TimeSlice) {
-	_, _ = fmt.Fprintln(w, "Gantt schedule")
-	_, _ = fmt.Fprint(w, "|")
-	for i := range gantt {
-		pid := fmt.Sprint(gantt[i].PID)
-		padding := strings.Repeat(" ", (8-len(pid))/2)
-		_, _ = fmt.Fprint(w, padding, pid, padding, "|")
+// firstDrain returns the first instant the CPU fell idle after having been
+// busy, i.e. the stop of the slice before the first gap in the chart. Idling
+// before the first slice does not count. ok is false if the CPU never drained.
+func firstDrain(gantt []TimeSlice) (at int64, ok bool) {
+	if len(gantt) == 0 {
+		return 0, false
 	}
-	_, _ = fmt.Fprintln(w)
-	for i := range gantt {
-		_, _ = fmt.Fprint(w, fmt.Sprint(gantt[i].Start), "\t")
-		if len(gantt)-1 == i {
-			_, _ = fmt.Fprint(w, fmt.Sprint(gantt[i].Stop))
+	lastStop := gantt[0].Stop
+	for i := 1; i < len(gantt); i++ {
+		if gantt[i].Start > lastStop {
+			return lastStop, true
+		}
+		if gantt[i].Stop > lastStop {
+			lastStop = gantt[i].Stop
 		}
 	}
-	_, _ = fmt.Fprintf(w, "\n\n")
-}
 
-func outputSchedule(w io.Writer, rows [][]string, wait, turnaround, throughput float64) {
-	_, _ = fmt.Fprintln(w, "Schedule table")
-	table := tablewriter.NewWriter(w)
-	table.SetHeader([]string{"ID", "Priority", "Burst", "Arrival", "Wait", "Turnaround", "Exit"})
-	table.AppendBulk(rows)
-	table.SetFooter([]string{"", "", "", "",
-		fmt.Sprintf("Average\n%.2f", wait),
-		fmt.Sprintf("Average\n%.2f", turnaround),
-		fmt.Sprintf("Throughput\n%.2f/t", throughput)})
-	table.Render()
+	return 0, false
 }
 
-//endregion
-
-//region Loading processes.
+// firstStart returns the start of the earliest slice the process ran in, or
+// nil if it never ran.
+func firstStart(gantt []TimeSlice, pid int64) *int64 {
+	var start *int64
+	for i := range gantt {
+		if gantt[i].PID == pid && (start == nil || gantt[i].Start < *start) {
+			v := gantt[i].Start
+			start = &v
+		}
+	}
 
-var ErrInvalidArgs = errors.New("invalid args")
+	return start
+}
 
-func loadProcesses(r io.Reader) ([]Process, error) {
-	rows, err := csv.NewReader(r).ReadAll()
-	if err != nil {
-		return nil, fmt.Errorf("%w: reading CSV", err)
+// contentionWait returns how long the process with the given PID and arrival
+// waited while some other process was running, between its arrival and its
+// final slice. Time the CPU spent idle never counts.
+func contentionWait(gantt []TimeSlice, pid, arrival int64) int64 {
+	var completion int64 = -1
+	for i := range gantt {
+		if gantt[i].PID == pid && gantt[i].Stop > completion {
+			completion = gantt[i].Stop
+		}
 	}
 
-	processes := make([]Process, len(rows))
-	for i := range rows {
-		processes[i].ProcessID = mustStrToInt(rows[i][0])
-		processes[i].BurstDuration = mustStrToInt(rows[i][1])
-		processes[i].ArrivalTime = mustStrToInt(rows[i][2])
-		if len(rows[i]) == 4 {
-			processes[i].Priority = mustStrToInt(rows[i][3])
+	return contentionWaitUntil(gantt, pid, arrival, completion)
+}
+
+// contentionWaitUntil is contentionWait up to end instead of the final
+// slice, for a process a -max-time run stopped before it could finish: it
+// was still waiting at the cap, however much of its burst it had run.
+func contentionWaitUntil(gantt []TimeSlice, pid, arrival, end int64) int64 {
+	var wait int64
+	for i := range gantt {
+		if gantt[i].PID == pid {
+			continue
+		}
+		start, stop := gantt[i].Start, gantt[i].Stop
+		if start < arrival {
+			start = arrival
+		}
+		if stop > end {
+			stop = end
+		}
+		if stop > start {
+			wait += stop - start
 		}
 	}
 
-	return processes, nil
+	return wait
 }
 
-func mustStrToInt(s string) int64 {
-	i, err := strconv.ParseInt(s, 10, 64)
-	if err != nil {
-		_, _ = fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+// addContentionWait fills in each row's contention wait, using the original
+// arrival times since some schedulers rewrite a row's arrival. Unfinished
+// rows count up to maxTime.
+func addContentionWait(r *ScheduleResult, arrivals map[int64]int64, maxTime int64) {
+	for i := range r.Rows {
+		pid := r.Rows[i].ProcessID
+		if r.Rows[i].Unfinished {
+			r.Rows[i].ContentionWait = contentionWaitUntil(r.Gantt, pid, arrivals[pid], maxTime)
+			continue
+		}
+		r.Rows[i].ContentionWait = contentionWait(r.Gantt, pid, arrivals[pid])
+	}
+}
+
+// computeMetrics derives the footer averages from the original processes and
+// the Gantt chart alone, so every scheduler measures them the same way. A
+// process completes at the end of its last slice; its turnaround runs from
+// arrival to completion and its wait is whatever part of that it was not
+// running. Throughput is processes completed per tick up to the last
+// completion.
+func computeMetrics(processes []Process, gantt []TimeSlice) Metrics {
+	return cappedMetrics(processes, gantt, 0, false)
+}
+
+// cappedMetrics is computeMetrics for a run stopped at maxTime (0 for a run
+// to completion). Processes arriving at or after the cap are left out.
+// Unfinished processes count up to the cap, or not at all when completedOnly
+// is set; either way only finished ones count towards throughput.
+func cappedMetrics(processes []Process, gantt []TimeSlice, maxTime int64, completedOnly bool) Metrics {
+	completion := make(map[int64]int64, len(processes))
+	ran := make(map[int64]int64, len(processes))
+	var lastStop, busy int64
+	for i := range gantt {
+		if gantt[i].Stop > completion[gantt[i].PID] {
+			completion[gantt[i].PID] = gantt[i].Stop
+		}
+		ran[gantt[i].PID] += gantt[i].Stop - gantt[i].Start
+		busy += gantt[i].Stop - gantt[i].Start
+		if gantt[i].Stop > lastStop {
+			lastStop = gantt[i].Stop
+		}
+	}
+
+	var count, completed, totalWait, totalContention, totalTurnaround, totalQueued float64
+	makespan := lastStop
+	for _, p := range processes {
+		if maxTime > 0 && p.ArrivalTime >= maxTime {
+			continue
+		}
+		end := completion[p.ProcessID]
+		finished := ran[p.ProcessID] >= p.BurstDuration
+		if !finished {
+			end = maxTime
+			makespan = maxTime
+		}
+		// The queue holds every process that has arrived, whether or not
+		// the averages below leave it out.
+		totalQueued += float64(end - p.ArrivalTime - ran[p.ProcessID])
+		if finished {
+			completed++
+		} else if completedOnly {
+			continue
+		}
+
+		turnaround := end - p.ArrivalTime
+		totalTurnaround += float64(turnaround)
+		totalWait += float64(turnaround - ran[p.ProcessID])
+		totalContention += float64(contentionWaitUntil(gantt, p.ProcessID, p.ArrivalTime, end))
+		count++
+	}
+
+	// A schedule of nothing but zero bursts does no work, so report no
+	// throughput rather than an infinite one, or one counting completions
+	// over time the CPU spent idle waiting for late arrivals.
+	var throughput float64
+	if busy > 0 {
+		throughput = completed / float64(lastStop)
+	}
+
+	return Metrics{
+		AverageWait:           average(totalWait, count),
+		AverageContentionWait: average(totalContention, count),
+		AverageTurnaround:     average(totalTurnaround, count),
+		Throughput:            throughput,
+		AverageQueueLength:    average(totalQueued, float64(makespan)),
+	}
+}
+
+// average is total / count, or 0 when there is nothing to average.
+func average(total, count float64) float64 {
+	if count == 0 {
+		return 0
+	}
+
+	return total / count
+}
+
+// capSchedule cuts r off at maxTime, as if the run had been stopped then:
+// slices are clipped to the cap, rows of processes that had not yet arrived
+// are dropped, and rows of processes still unfinished are marked as such.
+func capSchedule(r *ScheduleResult, processes []Process, maxTime int64, completedOnly bool) {
+	r.Gantt = clipSlices(r.Gantt, maxTime)
+	r.WaitIntervals = clipSlices(r.WaitIntervals, maxTime)
+
+	byPID := make(map[int64]Process, len(processes))
+	for _, p := range processes {
+		byPID[p.ProcessID] = p
+	}
+	ran := make(map[int64]int64, len(processes))
+	for _, slice := range r.Gantt {
+		ran[slice.PID] += slice.Stop - slice.Start
+	}
+
+	rows := make([]ScheduleRow, 0, len(r.Rows))
+	for _, row := range r.Rows {
+		p := byPID[row.ProcessID]
+		if p.ArrivalTime >= maxTime {
+			continue
+		}
+		if ran[p.ProcessID] < p.BurstDuration {
+			row.Unfinished = true
+			row.Turnaround = maxTime - p.ArrivalTime
+			row.Wait = row.Turnaround - ran[p.ProcessID]
+			row.Exit = 0
+		}
+		rows = append(rows, row)
+	}
+	r.Rows = rows
+	r.Metrics = cappedMetrics(processes, r.Gantt, maxTime, completedOnly)
+}
+
+// clipSlices drops the slices starting at or after maxTime and shortens any
+// still running then. A nil input stays nil.
+func clipSlices(slices []TimeSlice, maxTime int64) []TimeSlice {
+	if slices == nil {
+		return nil
+	}
+	clipped := make([]TimeSlice, 0, len(slices))
+	for _, slice := range slices {
+		if slice.Start >= maxTime {
+			continue
+		}
+		if slice.Stop > maxTime {
+			slice.Stop = maxTime
+		}
+		clipped = append(clipped, slice)
+	}
+
+	return clipped
+}
+
+//endregion
+
+//region Output helpers
+
+// outputResult renders a schedule result as the titled Gantt chart and
+// schedule table.
+func outputResult(w io.Writer, r ScheduleResult, s settings) {
+	outputTitle(w, r.Title)
+	outputGantt(w, r.Gantt, r.Labels, s)
+	outputSchedule(w, r.Rows, r.Metrics, s)
+	outputIdleGap(w, r.Gantt)
+	_, _ = fmt.Fprintf(w, "Average ready queue length: %s\n", formatAverage(r.Metrics.AverageQueueLength, s))
+	if s.histogramBuckets > 0 {
+		outputTurnaroundHistogram(w, turnaroundHistogram(r.Rows, s.histogramBuckets))
+	}
+	if s.throughputWindow > 0 {
+		outputThroughputWindow(w, throughputSeries(r, s.throughputWindow), s)
+	}
+	if s.showWaitingIntervals && r.WaitIntervals != nil {
+		outputWaitIntervals(w, r.WaitIntervals)
+	}
+	if r.Predictions != nil {
+		outputPredictions(w, r.Rows, r.Predictions)
+	}
+	if len(r.Dropped) > 0 {
+		outputDropped(w, r.Dropped)
+	}
+	if r.Summary != nil {
+		outputProcessSummary(w, r.Summary, s)
+	}
+}
+
+// outputDropped lists the processes turned away by a full ready queue.
+func outputDropped(w io.Writer, dropped []int64) {
+	ids := make([]string, len(dropped))
+	for i, pid := range dropped {
+		ids[i] = fmt.Sprint(pid)
+	}
+	_, _ = fmt.Fprintf(w, "Dropped (ready queue full): %s\n", strings.Join(ids, ", "))
+}
+
+func outputTitle(w io.Writer, title string) {
+	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+	_, _ = fmt.Fprintln(w, strings.Repeat(" ", len(title)/2), title)
+	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+}
+
+// outputGantt renders the chart; a positive s.maxGanttSlices caps how many
+// slices are drawn, noting how many were left out. Slices whose PID is in
+// labels are drawn with the label in place of the PID.
+func outputGantt(w io.Writer, gantt []TimeSlice, labels map[int64]string, s settings) {
+	shown := shownSlices(len(gantt), s)
+	hidden := len(gantt) - shown
+	gantt = gantt[:shown]
+
+	_, _ = fmt.Fprintln(w, "Gantt schedule")
+	if s.ganttProportional {
+		outputProportionalGantt(w, gantt, labels)
+	} else {
+		outputFixedGantt(w, gantt, labels)
+	}
+	if hidden > 0 {
+		_, _ = fmt.Fprintf(w, "\n… (truncated, %d more)", hidden)
+	}
+	_, _ = fmt.Fprintf(w, "\n\n")
+}
+
+// shownSlices is how many of a chart's n slices outputGantt draws.
+func shownSlices(n int, s settings) int {
+	if s.maxGanttSlices > 0 && n > s.maxGanttSlices {
+		return s.maxGanttSlices
+	}
+
+	return n
+}
+
+// ganttLabel is the text drawn in a slice's cell: its label if it has one in
+// labels, otherwise its PID.
+func ganttLabel(pid int64, labels map[int64]string) string {
+	if label, ok := labels[pid]; ok {
+		return label
+	}
+
+	return fmt.Sprint(pid)
+}
+
+// outputFixedGantt draws every slice as the same width cell, with the times
+// tab-separated underneath. Widths are measured in terminal columns, so
+// multi-byte and double-width labels stay centred.
+func outputFixedGantt(w io.Writer, gantt []TimeSlice, labels map[int64]string) {
+	_, _ = fmt.Fprint(w, "|")
+	for i := range gantt {
+		label := ganttLabel(gantt[i].PID, labels)
+		padding := ""
+		if width := runewidth.StringWidth(label); width < 8 {
+			padding = strings.Repeat(" ", (8-width)/2)
+		}
+		_, _ = fmt.Fprint(w, padding, label, padding, "|")
+	}
+	_, _ = fmt.Fprintln(w)
+	for i := range gantt {
+		_, _ = fmt.Fprint(w, fmt.Sprint(gantt[i].Start), "\t")
+		if len(gantt)-1 == i {
+			_, _ = fmt.Fprint(w, fmt.Sprint(gantt[i].Stop))
+		}
+	}
+}
+
+// outputProportionalGantt draws each slice, and each idle gap, as a cell one
+// column wide per tick of its duration, widened if needed to fit its label
+// with a space either side. Each time is printed under the boundary it marks.
+func outputProportionalGantt(w io.Writer, gantt []TimeSlice, labels map[int64]string) {
+	type cell struct {
+		label       string
+		start, stop int64
+	}
+	cells := make([]cell, 0, len(gantt))
+	for i := range gantt {
+		if i > 0 && gantt[i].Start > gantt[i-1].Stop {
+			cells = append(cells, cell{start: gantt[i-1].Stop, stop: gantt[i].Start})
+		}
+		cells = append(cells, cell{label: ganttLabel(gantt[i].PID, labels), start: gantt[i].Start, stop: gantt[i].Stop})
+	}
+
+	// barWidth tracks the bar's width in columns, which is its byte length
+	// only when every label is ASCII.
+	var bar, axis strings.Builder
+	bar.WriteString("|")
+	barWidth := 1
+	for i, c := range cells {
+		labelWidth := runewidth.StringWidth(c.label)
+		width := int(c.stop - c.start)
+		if min := labelWidth + 2; width < min {
+			width = min
+		}
+		left := (width - labelWidth) / 2
+		bar.WriteString(strings.Repeat(" ", left) + c.label + strings.Repeat(" ", width-left-labelWidth) + "|")
+		barWidth += width + 1
+
+		// Align each time with the bar's boundary, nudging right when the
+		// previous time is still too long to fit.
+		if i == 0 {
+			axis.WriteString(fmt.Sprint(c.start))
+		}
+		boundary := barWidth - 1
+		if pad := boundary - axis.Len(); pad > 0 {
+			axis.WriteString(strings.Repeat(" ", pad))
+		} else {
+			axis.WriteString(" ")
+		}
+		axis.WriteString(fmt.Sprint(c.stop))
+	}
+
+	_, _ = fmt.Fprintln(w, bar.String())
+	_, _ = fmt.Fprint(w, axis.String())
+}
+
+func outputIdleGap(w io.Writer, gantt []TimeSlice) {
+	start, length := longestIdleGap(gantt)
+	if length == 0 {
+		_, _ = fmt.Fprintln(w, "Longest idle gap: none")
+	} else {
+		_, _ = fmt.Fprintf(w, "Longest idle gap: %d (from %d to %d)\n", length, start, start+length)
+	}
+	if at, ok := firstDrain(gantt); ok {
+		_, _ = fmt.Fprintf(w, "First drained: %d\n", at)
+	} else {
+		_, _ = fmt.Fprintln(w, "First drained: never")
+	}
+}
+
+// outputWaitIntervals lists, per process in PID order, every span it spent
+// waiting in the ready queue along with their total.
+func outputWaitIntervals(w io.Writer, intervals []TimeSlice) {
+	byPID := make(map[int64][]TimeSlice)
+	pids := make([]int64, 0)
+	for i := range intervals {
+		if _, ok := byPID[intervals[i].PID]; !ok {
+			pids = append(pids, intervals[i].PID)
+		}
+		byPID[intervals[i].PID] = append(byPID[intervals[i].PID], intervals[i])
+	}
+	sort.Slice(pids, func(i, j int) bool { return pids[i] < pids[j] })
+
+	_, _ = fmt.Fprintln(w, "Waiting intervals")
+	if len(pids) == 0 {
+		_, _ = fmt.Fprintln(w, "none")
+	}
+	for _, pid := range pids {
+		var total int64
+		spans := make([]string, len(byPID[pid]))
+		for i, span := range byPID[pid] {
+			spans[i] = fmt.Sprintf("%d-%d", span.Start, span.Stop)
+			total += span.Stop - span.Start
+		}
+		_, _ = fmt.Fprintf(w, "%d: %s (total %d)\n", pid, strings.Join(spans, " "), total)
+	}
+}
+
+// newTable returns a table writer in the style chosen by the settings: the
+// default bordered grid, or a borderless, tightly spaced one for
+// -compact-table.
+func newTable(w io.Writer, s settings) *tablewriter.Table {
+	table := tablewriter.NewWriter(w)
+	if s.compactTable {
+		table.SetBorder(false)
+		table.SetHeaderLine(false)
+		table.SetRowSeparator("")
+		table.SetColumnSeparator("")
+		table.SetCenterSeparator("")
+		table.SetTablePadding(" ")
+		table.SetNoWhiteSpace(true)
+	}
+
+	return table
+}
+
+// formatAverage renders v with s.precision decimals, rounded per s.rounding.
+func formatAverage(v float64, s settings) string {
+	scale := math.Pow(10, float64(s.precision))
+	// The epsilon keeps values like 0.29, stored as 0.28999..., from
+	// flooring a whole digit too low (or ceiling a digit too high).
+	switch s.rounding {
+	case roundFloor:
+		v = math.Floor(v*scale+1e-9) / scale
+	case roundCeil:
+		v = math.Ceil(v*scale-1e-9) / scale
+	default:
+		v = math.Round(v*scale) / scale
+	}
+
+	return strconv.FormatFloat(v, 'f', s.precision, 64)
+}
+
+// outputPredictions lists the burst predicted for each process, in table
+// order, next to the burst it actually needed.
+func outputPredictions(w io.Writer, rows []ScheduleRow, predictions map[int64]float64) {
+	_, _ = fmt.Fprintln(w, "Predicted bursts")
+	for i := range rows {
+		_, _ = fmt.Fprintf(w, "%d: predicted %.2f, actual %d\n",
+			rows[i].ProcessID, predictions[rows[i].ProcessID], rows[i].Burst)
+	}
+}
+
+func outputSchedule(w io.Writer, rows []ScheduleRow, metrics Metrics, s settings) {
+	_, _ = fmt.Fprintln(w, "Schedule table")
+	table := newTable(w, s)
+	header := []string{"ID", "Priority", "Burst", "Arrival", "First start", "Wait", "Contention", "Turnaround", "Exit"}
+	footer := []string{"", "", "", "", ""}
+	if s.showLine {
+		header = append([]string{"Line"}, header...)
+		footer = append(footer, "")
+	}
+	table.SetHeader(header)
+	// Right-aligned like the numbers, so an unfinished row's "-" lines up.
+	table.SetAlignment(tablewriter.ALIGN_RIGHT)
+	// Once a wait is clamped, the footer averages the waits as shown.
+	var clamped bool
+	var shownWait, averaged float64
+	for i := range rows {
+		var line []string
+		if s.showLine {
+			line = []string{fmt.Sprint(rows[i].Line)}
+		}
+		priority := fmt.Sprint(rows[i].Priority)
+		if rows[i].NoPriority {
+			priority = "n/a"
+		}
+		exit := fmt.Sprint(rows[i].Exit)
+		if rows[i].Unfinished {
+			exit = "-"
+		}
+		start := "-"
+		if rows[i].FirstStart != nil {
+			start = fmt.Sprint(*rows[i].FirstStart)
+		}
+		wait := rows[i].Wait
+		if s.clampWait && wait < 0 {
+			s.warnings.warn(warnClampedWait, fmt.Sprintf("clamped PID %d's wait of %d to 0", rows[i].ProcessID, wait))
+			wait = 0
+			clamped = true
+		}
+		if !s.avgCompletedOnly || !rows[i].Unfinished {
+			shownWait += float64(wait)
+			averaged++
+		}
+		table.Append(append(line,
+			fmt.Sprint(rows[i].ProcessID),
+			priority,
+			fmt.Sprint(rows[i].Burst),
+			fmt.Sprint(rows[i].Arrival),
+			start,
+			fmt.Sprint(wait),
+			fmt.Sprint(rows[i].ContentionWait),
+			fmt.Sprint(rows[i].Turnaround),
+			exit,
+		))
+	}
+	averageWait := metrics.AverageWait
+	if clamped {
+		averageWait = average(shownWait, averaged)
+	}
+	table.SetFooter(append(footer,
+		"Average\n"+formatAverage(averageWait, s),
+		"Average\n"+formatAverage(metrics.AverageContentionWait, s),
+		"Average\n"+formatAverage(metrics.AverageTurnaround, s),
+		"Throughput\n"+formatAverage(metrics.Throughput, s)+"/t"))
+	table.Render()
+}
+
+// outputAlgorithmHelp lists every registered scheduler with what it does and
+// the flags that tune it.
+func outputAlgorithmHelp(w io.Writer) {
+	_, _ = fmt.Fprintln(w, "Algorithms (select with -algo):")
+	for _, sc := range schedulers {
+		_, _ = fmt.Fprintf(w, "  %-9s %s: %s\n", sc.name, sc.title, sc.description)
+		if len(sc.params) > 0 {
+			_, _ = fmt.Fprintf(w, "  %-9s parameters: %s\n", "", strings.Join(sc.params, ", "))
+		}
+	}
+}
+
+// outputJSONAll writes every result as a single JSON document of the form
+// {"algorithms": [...]}, suitable as one dashboard feed.
+func outputJSONAll(w io.Writer, results []ScheduleResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(struct {
+		Algorithms []ScheduleResult `json:"algorithms"`
+	}{results}); err != nil {
+		return fmt.Errorf("%w: encoding JSON", err)
+	}
+
+	return nil
+}
+
+//endregion
+
+//region Loading processes.
+
+var (
+	ErrInvalidArgs = errors.New("invalid args")
+	ErrInvalidRow  = errors.New("invalid process row")
+)
+
+// loadProcesses parses CSV records of the form
+// <ProcessID>,<Burst Duration>,<Arrival Time>[,<Priority>[,<Label>[,<Remaining>[,<Group>]]]].
+// Labels may contain commas as long as they are quoted, e.g. 1,5,0,2,"build, test".
+// A remaining burst marks a process already running at t=0 with only that
+// much of its burst left. A group names a batch arrival event, applied by
+// -group-events after loading. With s.arrivalFormat set to timestamp, arrivals are
+// RFC3339 times instead, converted to whole seconds after the earliest one.
+//
+// Rows may be ragged, so some processes can give a priority while others
+// leave it out. Those without one rank by s.noPriorityRank: lowest puts them
+// one step after the least important given priority, highest one step before
+// the most important. When no row has a priority, they all stay at 0.
+//
+// A malformed row fails the whole load, unless s.errorPolicy is skip: then the
+// row is dropped, warn is told why, and loading carries on. Errors reading r
+// itself always fail.
+func loadProcesses(r io.Reader, s settings, warn func(string)) ([]Process, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	// parseProcess checks each row's field count itself.
+	reader.FieldsPerRecord = -1
+
+	// reject applies s.errorPolicy to a malformed row, returning the error
+	// to fail with, or nil once the row has been skipped with a warning.
+	reject := func(err error) error {
+		if s.errorPolicy != errorPolicySkip {
+			return err
+		}
+		warn(fmt.Sprintf("skipping %v", err))
+		return nil
+	}
+
+	processes := make([]Process, 0)
+	var (
+		stamps []time.Time
+		rows   []int
+	)
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if err != nil && !errors.As(err, &parseErr) {
+			return nil, fmt.Errorf("%w: reading CSV", err)
+		}
+
+		var (
+			p     Process
+			stamp time.Time
+		)
+		if err == nil && s.arrivalFormat == arrivalTimestamp {
+			stamp, err = parseArrivalStamp(record, row)
+		}
+		if err == nil {
+			p, err = parseProcess(record, row)
+			p.Line, _ = reader.FieldPos(0)
+		} else if parseErr != nil {
+			err = fmt.Errorf("%w: row %d: %v", ErrInvalidRow, row, err)
+		}
+		if err != nil {
+			if err := reject(err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		processes = append(processes, p)
+		stamps = append(stamps, stamp)
+		rows = append(rows, row)
+	}
+
+	var earliest time.Time
+	for i, stamp := range stamps {
+		if i == 0 || stamp.Before(earliest) {
+			earliest = stamp
+		}
+	}
+	// Skipping a row for its stamp never moves the earliest one, since the
+	// earliest row can neither arrive too late nor after 0.
+	var (
+		kept  = processes[:0]
+		bound horizon
+	)
+	for i, p := range processes {
+		var err error
+		if s.arrivalFormat == arrivalTimestamp {
+			err = stampArrival(&p, stamps[i], earliest, rows[i])
+		}
+		if err == nil {
+			err = bound.add(p, rows[i])
+		}
+		if err != nil {
+			if err := reject(err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		kept = append(kept, p)
+	}
+	processes = kept
+	defaultPriorities(processes, s.noPriorityRank)
+
+	return processes, nil
+}
+
+// defaultPriorities gives every process with NoPriority set a priority just
+// past the given ones, at the end named by missing. At the int64 bounds there
+// is no step further, so the default ties with the extreme given priority.
+// NoPriority stays set, so tables still show such processes as having no
+// priority of their own.
+func defaultPriorities(processes []Process, missing string) {
+	var (
+		least, most int64
+		given       bool
+	)
+	for i := range processes {
+		if processes[i].NoPriority {
+			continue
+		}
+		if !given || processes[i].Priority > least {
+			least = processes[i].Priority
+		}
+		if !given || processes[i].Priority < most {
+			most = processes[i].Priority
+		}
+		given = true
+	}
+	if !given {
+		return
+	}
+
+	priority := least
+	if missing == missingHighest {
+		priority = most
+		if priority > math.MinInt64 {
+			priority--
+		}
+	} else if priority < math.MaxInt64 {
+		priority++
+	}
+	for i := range processes {
+		if processes[i].NoPriority {
+			processes[i].Priority = priority
+		}
+	}
+}
+
+// stampArrival sets p's arrival to the whole seconds stamp falls after
+// earliest. Rows with stamps are only fully checked here, once their real
+// arrival is known: a gap too long to count in a time.Duration (about 292
+// years) is an error, as is a remaining burst on a row that turns out not to
+// arrive at 0.
+func stampArrival(p *Process, stamp, earliest time.Time, row int) error {
+	gap := stamp.Sub(earliest)
+	if !earliest.Add(gap).Equal(stamp) {
+		return fmt.Errorf("%w: row %d arrives too long after the earliest arrival, %s, to count in seconds",
+			ErrInvalidRow, row, earliest.Format(time.RFC3339))
+	}
+	p.ArrivalTime = int64(gap / time.Second)
+	if p.Remaining > 0 && p.ArrivalTime != 0 {
+		return fmt.Errorf("%w: row %d has a remaining burst but arrives at %d, not 0",
+			ErrInvalidRow, row, p.ArrivalTime)
+	}
+
+	return nil
+}
+
+// horizon bounds when a workload can finish: no process completes later
+// than the latest arrival plus every burst. Keeping that within an int64
+// keeps every completion, wait and turnaround a scheduler works out within
+// one too.
+type horizon struct {
+	latest, bursts int64
+}
+
+// add takes p into the bound, or fails without changing it if p would push
+// the bound past the largest int64.
+func (h *horizon) add(p Process, row int) error {
+	latest := h.latest
+	if p.ArrivalTime > latest {
+		latest = p.ArrivalTime
+	}
+	if p.BurstDuration > math.MaxInt64-h.bursts || latest > math.MaxInt64-h.bursts-p.BurstDuration {
+		return fmt.Errorf("%w: row %d would let the workload run past tick %d", ErrInvalidRow, row, int64(math.MaxInt64))
+	}
+	h.latest, h.bursts = latest, h.bursts+p.BurstDuration
+
+	return nil
+}
+
+// checkHorizon holds a generated workload to the same horizon as a loaded
+// one, counting its processes as rows.
+func checkHorizon(processes []Process) error {
+	var bound horizon
+	for i := range processes {
+		if err := bound.add(processes[i], i+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseArrivalStamp reads the RFC3339 arrival of a -arrival-format timestamp
+// record and replaces it with a placeholder tick, so parseProcess can handle
+// the rest of the record; the real tick is only known once every arrival has
+// been read.
+func parseArrivalStamp(record []string, row int) (time.Time, error) {
+	if len(record) < 3 {
+		return time.Time{}, nil // parseProcess reports the short row
+	}
+	stamp, err := time.Parse(time.RFC3339, strings.TrimSpace(record[2]))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: row %d arrival %q is not an RFC3339 timestamp",
+			ErrInvalidRow, row, record[2])
+	}
+	record[2] = "0"
+
+	return stamp, nil
+}
+
+// parseProcess converts one CSV record, the row-th in the file, to a Process.
+func parseProcess(record []string, row int) (Process, error) {
+	// Field counts come from the parsed record, so quoted commas in a
+	// label never count as extra fields.
+	if n := len(record); n < 3 || n > 7 {
+		return Process{}, fmt.Errorf("%w: row %d has %d fields, want 3 to 7", ErrInvalidRow, row, n)
+	}
+
+	var (
+		p      Process
+		fields = []*int64{&p.ProcessID, &p.BurstDuration, &p.ArrivalTime, &p.Priority}
+	)
+	p.NoPriority = len(record) < 4
+	for i := 0; i < len(fields) && i < len(record); i++ {
+		v, err := strconv.ParseInt(strings.TrimSpace(record[i]), 10, 64)
+		if err != nil {
+			return Process{}, fmt.Errorf("%w: row %d field %d: %v", ErrInvalidRow, row, i+1, err)
+		}
+		*fields[i] = v
+	}
+	if err := checkTimes(p, row); err != nil {
+		return Process{}, err
+	}
+	if len(record) >= 5 {
+		// Left as-is: quoted labels keep their inner spacing.
+		p.Label = record[4]
+	}
+	if len(record) >= 6 && strings.TrimSpace(record[5]) != "" {
+		v, err := strconv.ParseInt(strings.TrimSpace(record[5]), 10, 64)
+		if err != nil {
+			return Process{}, fmt.Errorf("%w: row %d field 6: %v", ErrInvalidRow, row, err)
+		}
+		if v < 1 || v > p.BurstDuration {
+			return Process{}, fmt.Errorf("%w: row %d remaining burst %d is outside 1..%d",
+				ErrInvalidRow, row, v, p.BurstDuration)
+		}
+		if p.ArrivalTime != 0 {
+			return Process{}, fmt.Errorf("%w: row %d has a remaining burst but arrives at %d, not 0",
+				ErrInvalidRow, row, p.ArrivalTime)
+		}
+		p.Remaining = v
+	}
+	if len(record) == 7 {
+		p.Group = strings.TrimSpace(record[6])
+	}
+
+	return p, nil
+}
+
+// checkTimes rejects the row-th process if it has a negative burst or
+// arrives before 0.
+func checkTimes(p Process, row int) error {
+	if p.BurstDuration < 0 {
+		return fmt.Errorf("%w: row %d has a negative burst, %d", ErrInvalidRow, row, p.BurstDuration)
+	}
+	if p.ArrivalTime < 0 {
+		return fmt.Errorf("%w: row %d arrives before 0, at %d", ErrInvalidRow, row, p.ArrivalTime)
+	}
+
+	return nil
+}
+
+// checkMonotonicIDs reports the first row whose ID breaks the 1..N sequence,
+// by the file line it was read from.
+func checkMonotonicIDs(processes []Process) error {
+	for i := range processes {
+		if want := int64(i + 1); processes[i].ProcessID != want {
+			return fmt.Errorf("%w: row %d has ID %d, want %d (IDs must run 1..N in order)",
+				ErrInvalidRow, processes[i].Line, processes[i].ProcessID, want)
+		}
+	}
+
+	return nil
+}
+
+// filterIDs keeps only the processes named in ids, in their original order,
+// and fails if any requested ID is missing.
+func filterIDs(processes []Process, ids []int64) ([]Process, error) {
+	wanted := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = false
+	}
+
+	filtered := make([]Process, 0, len(ids))
+	for i := range processes {
+		if _, ok := wanted[processes[i].ProcessID]; ok {
+			wanted[processes[i].ProcessID] = true
+			filtered = append(filtered, processes[i])
+		}
+	}
+	for _, id := range ids {
+		if !wanted[id] {
+			return nil, fmt.Errorf("%w: process ID %d is not in the input", ErrInvalidArgs, id)
+		}
+	}
+
+	return filtered, nil
+}
+
+// offsetArrivals shifts every arrival by offset ticks, which may be negative
+// as long as no arrival ends up before t=0.
+func offsetArrivals(processes []Process, offset int64) ([]Process, error) {
+	shifted := make([]Process, len(processes))
+	for i := range processes {
+		shifted[i] = processes[i]
+		shifted[i].ArrivalTime += offset
+		if shifted[i].ArrivalTime < 0 {
+			return nil, fmt.Errorf("%w: offset %d moves PID %d's arrival before 0",
+				ErrInvalidArgs, offset, processes[i].ProcessID)
+		}
+	}
+
+	return shifted, nil
+}
+
+// allZeroBursts reports whether there are processes and none has any burst.
+func allZeroBursts(processes []Process) bool {
+	for _, p := range processes {
+		if p.BurstDuration != 0 {
+			return false
+		}
+	}
+
+	return len(processes) > 0
+}
+
+// meanBurst is the average burst duration of processes, 0 when there are none.
+func meanBurst(processes []Process) float64 {
+	if len(processes) == 0 {
+		return 0
+	}
+	var total int64
+	for _, p := range processes {
+		total += p.BurstDuration
+	}
+
+	return float64(total) / float64(len(processes))
+}
+
+// quantumFromFraction sizes a round-robin quantum as frac of the mean burst,
+// rounded to the nearest tick and never less than one.
+func quantumFromFraction(processes []Process, frac float64) int64 {
+	q := int64(math.Round(frac * meanBurst(processes)))
+	if q < 1 {
+		return 1
+	}
+
+	return q
+}
+
+// dumpProcesses writes processes back out in the input CSV format, so the
+// effective workload after every load-time transformation can be inspected
+// or fed back in.
+func dumpProcesses(w io.Writer, processes []Process) error {
+	// A row can only leave its priority out by ending there, so a process
+	// without one that has a label, remaining burst or group must be written
+	// with its defaulted priority. Then every row is, since rows still left
+	// without one would be defaulted past it on reload, to a different value.
+	allPriorities := false
+	for _, p := range processes {
+		if p.NoPriority && (p.Label != "" || p.Remaining > 0 || p.Group != "") {
+			allPriorities = true
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	for _, p := range processes {
+		record := []string{
+			fmt.Sprint(p.ProcessID),
+			fmt.Sprint(p.BurstDuration),
+			fmt.Sprint(p.ArrivalTime),
+		}
+		if !p.NoPriority || allPriorities {
+			record = append(record, fmt.Sprint(p.Priority))
+		}
+		if p.Label != "" || p.Remaining > 0 || p.Group != "" {
+			record = append(record, p.Label)
+		}
+		if p.Remaining > 0 || p.Group != "" {
+			remaining := ""
+			if p.Remaining > 0 {
+				remaining = fmt.Sprint(p.Remaining)
+			}
+			record = append(record, remaining)
+		}
+		if p.Group != "" {
+			record = append(record, p.Group)
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("%w: writing processes", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("%w: writing processes", err)
+	}
+
+	return nil
+}
+
+// parseIDList parses a comma-separated list of process IDs such as "1,3,5".
+func parseIDList(v string) ([]int64, error) {
+	fields := strings.Split(v, ",")
+	ids := make([]int64, len(fields))
+	for i, field := range fields {
+		id, err := strconv.ParseInt(strings.TrimSpace(field), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad process ID %q", ErrInvalidArgs, field)
+		}
+		ids[i] = id
 	}
 
-	return i
+	return ids, nil
 }
 
 //endregion
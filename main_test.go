@@ -1,12 +1,18 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"math"
 	"os"
 	"path"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 	"testing"
 	"testing/iotest"
@@ -67,13 +73,17 @@ func TestFCFSSchedule(t *testing.T) {
 func Test_loadProcesses(t *testing.T) {
 	t.Parallel()
 	type args struct {
-		r io.Reader
+		r              io.Reader
+		errorPolicy    string
+		arrivalFormat  string
+		noPriorityRank string
 	}
 	tests := []struct {
-		name    string
-		args    args
-		want    []Process
-		wantErr error
+		name         string
+		args         args
+		want         []Process
+		wantErr      error
+		wantWarnings int
 	}{
 		{
 			name: "bad CSV",
@@ -95,37 +105,405 @@ func Test_loadProcesses(t *testing.T) {
 					ArrivalTime:   0,
 					BurstDuration: 5,
 					Priority:      2,
+					Line:          1,
+				},
+				{
+					ProcessID:     2,
+					ArrivalTime:   3,
+					BurstDuration: 9,
+					Priority:      1,
+					Line:          2,
+				},
+				{
+					ProcessID:     3,
+					ArrivalTime:   3,
+					BurstDuration: 6,
+					Priority:      3,
+					Line:          3,
+				},
+			},
+		},
+		{
+			name: "quoted label with comma",
+			args: args{
+				r: strings.NewReader(`1,5,0,2,"build, test"
+2, 9, 3, 1, "lint"`),
+			},
+			want: []Process{
+				{
+					ProcessID:     1,
+					ArrivalTime:   0,
+					BurstDuration: 5,
+					Priority:      2,
+					Label:         "build, test",
+					Line:          1,
+				},
+				{
+					ProcessID:     2,
+					ArrivalTime:   3,
+					BurstDuration: 9,
+					Priority:      1,
+					Label:         "lint",
+					Line:          2,
+				},
+			},
+		},
+		{
+			name: "line numbers follow the file",
+			args: args{
+				r: strings.NewReader(`1,5,0,2,"two
+lines"
+
+2,9,3,1,lint`),
+			},
+			want: []Process{
+				{
+					ProcessID:     1,
+					ArrivalTime:   0,
+					BurstDuration: 5,
+					Priority:      2,
+					Label:         "two\nlines",
+					Line:          1,
+				},
+				{
+					ProcessID:     2,
+					ArrivalTime:   3,
+					BurstDuration: 9,
+					Priority:      1,
+					Label:         "lint",
+					Line:          4,
+				},
+			},
+		},
+		{
+			name: "no priority column",
+			args: args{
+				r: strings.NewReader(`1,5,0
+2,9,3`),
+			},
+			want: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, NoPriority: true, Line: 1},
+				{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, NoPriority: true, Line: 2},
+			},
+		},
+		{
+			name: "mixed priority columns rank missing ones lowest",
+			args: args{
+				r: strings.NewReader(`1,5,0,2
+2,9,3
+3,6,3,-1`),
+			},
+			want: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2, Line: 1},
+				{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 3, NoPriority: true, Line: 2},
+				{ProcessID: 3, ArrivalTime: 3, BurstDuration: 6, Priority: -1, Line: 3},
+			},
+		},
+		{
+			name: "mixed priority columns rank missing ones highest",
+			args: args{
+				r: strings.NewReader(`1,5,0
+2,9,3,1
+3,6,3,4`),
+				noPriorityRank: missingHighest,
+			},
+			want: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 0, NoPriority: true, Line: 1},
+				{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1, Line: 2},
+				{ProcessID: 3, ArrivalTime: 3, BurstDuration: 6, Priority: 4, Line: 3},
+			},
+		},
+		{
+			name: "missing priorities saturate past the int64 bounds",
+			args: args{
+				r: strings.NewReader(`1,5,0,1
+2,5,0
+3,5,0,9223372036854775807`),
+			},
+			want: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 1, Line: 1},
+				{ProcessID: 2, ArrivalTime: 0, BurstDuration: 5, Priority: math.MaxInt64, NoPriority: true, Line: 2},
+				{ProcessID: 3, ArrivalTime: 0, BurstDuration: 5, Priority: math.MaxInt64, Line: 3},
+			},
+		},
+		{
+			name: "missing priorities saturate past the int64 bounds, highest",
+			args: args{
+				r: strings.NewReader(`1,5,0,-9223372036854775808
+2,5,0`),
+				noPriorityRank: missingHighest,
+			},
+			want: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: math.MinInt64, Line: 1},
+				{ProcessID: 2, ArrivalTime: 0, BurstDuration: 5, Priority: math.MinInt64, NoPriority: true, Line: 2},
+			},
+		},
+		{
+			name: "timestamp arrivals",
+			args: args{
+				r: strings.NewReader(`1,5,2024-03-01T10:00:30Z,2
+2,9,2024-03-01T12:00:00+02:00,1`),
+				arrivalFormat: arrivalTimestamp,
+			},
+			want: []Process{
+				{ProcessID: 1, ArrivalTime: 30, BurstDuration: 5, Priority: 2, Line: 1},
+				{ProcessID: 2, ArrivalTime: 0, BurstDuration: 9, Priority: 1, Line: 2},
+			},
+		},
+		{
+			name: "timestamp arrival after 0 with a remaining burst",
+			args: args{
+				r: strings.NewReader(`1,5,2024-03-01T10:00:30Z,0,,2
+2,9,2024-03-01T10:00:00Z,1`),
+				arrivalFormat: arrivalTimestamp,
+			},
+			wantErr: ErrInvalidRow,
+		},
+		{
+			name: "timestamp gap too long to count",
+			args: args{
+				r: strings.NewReader(`1,5,9999-12-31T23:59:59Z
+2,5,0001-01-01T00:00:00Z`),
+				arrivalFormat: arrivalTimestamp,
+			},
+			wantErr: ErrInvalidRow,
+		},
+		{
+			name: "skip rows whose timestamps fail",
+			args: args{
+				r: strings.NewReader(`1,5,9999-12-31T23:59:59Z
+2,5,0001-01-01T00:00:00Z,1,,2
+3,9,0001-01-01T00:00:10Z`),
+				arrivalFormat: arrivalTimestamp,
+				errorPolicy:   errorPolicySkip,
+			},
+			want: []Process{
+				{ProcessID: 2, ArrivalTime: 0, BurstDuration: 5, Priority: 1, Remaining: 2, Line: 2},
+				{ProcessID: 3, ArrivalTime: 10, BurstDuration: 9, Priority: 2, NoPriority: true, Line: 3},
+			},
+			wantWarnings: 1,
+		},
+		{
+			name: "bad timestamp",
+			args: args{
+				r:             strings.NewReader(`1,5,10:00,2`),
+				arrivalFormat: arrivalTimestamp,
+			},
+			wantErr: ErrInvalidRow,
+		},
+		{
+			name: "remaining burst",
+			args: args{
+				r: strings.NewReader(`1,5,0,2,,2
+2,9,3,1,lint,`),
+			},
+			want: []Process{
+				{
+					ProcessID:     1,
+					ArrivalTime:   0,
+					BurstDuration: 5,
+					Priority:      2,
+					Remaining:     2,
+					Line:          1,
 				},
 				{
 					ProcessID:     2,
 					ArrivalTime:   3,
 					BurstDuration: 9,
 					Priority:      1,
+					Label:         "lint",
+					Line:          2,
+				},
+			},
+		},
+		{
+			name: "remaining burst longer than the burst",
+			args: args{
+				r: strings.NewReader(`1,5,0,2,,6`),
+			},
+			wantErr: ErrInvalidRow,
+		},
+		{
+			name: "too many fields",
+			args: args{
+				r: strings.NewReader(`1,5,0,2,build,3,nightly,extra`),
+			},
+			wantErr: ErrInvalidRow,
+		},
+		{
+			name: "negative burst",
+			args: args{
+				r: strings.NewReader(`1,-2,0
+2,2,0`),
+			},
+			wantErr: ErrInvalidRow,
+		},
+		{
+			name: "negative arrival",
+			args: args{
+				r: strings.NewReader(`1,5,-1`),
+			},
+			wantErr: ErrInvalidRow,
+		},
+		{
+			name: "completion past the largest int64",
+			args: args{
+				r: strings.NewReader(`1,5,9223372036854775800
+2,5,0`),
+			},
+			wantErr: ErrInvalidRow,
+		},
+		{
+			name: "bad number fails by default",
+			args: args{
+				r: strings.NewReader(`1,5,0,2
+2,nine,3,1`),
+			},
+			wantErr: ErrInvalidRow,
+		},
+		{
+			name: "skip bad rows",
+			args: args{
+				r: strings.NewReader(`1,5,0,2
+2,nine,3,1
+3,6,3,3
+4,2,5",1
+5,1,1,1`),
+				errorPolicy: errorPolicySkip,
+			},
+			want: []Process{
+				{
+					ProcessID:     1,
+					ArrivalTime:   0,
+					BurstDuration: 5,
+					Priority:      2,
+					Line:          1,
 				},
 				{
 					ProcessID:     3,
 					ArrivalTime:   3,
 					BurstDuration: 6,
 					Priority:      3,
+					Line:          3,
+				},
+				{
+					ProcessID:     5,
+					ArrivalTime:   1,
+					BurstDuration: 1,
+					Priority:      1,
+					Line:          5,
 				},
 			},
+			wantWarnings: 2,
 		},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			got, err := loadProcesses(tt.args.r)
+			s := defaultSettings()
+			if tt.args.errorPolicy != "" {
+				s.errorPolicy = tt.args.errorPolicy
+			}
+			if tt.args.arrivalFormat != "" {
+				s.arrivalFormat = tt.args.arrivalFormat
+			}
+			if tt.args.noPriorityRank != "" {
+				s.noPriorityRank = tt.args.noPriorityRank
+			}
+			var warnings []string
+			got, err := loadProcesses(tt.args.r, s, func(msg string) {
+				warnings = append(warnings, msg)
+			})
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("loadProcesses() = %v, want %v", got, tt.want)
 			}
 			if !errors.Is(err, tt.wantErr) {
 				t.Errorf("error = %v, want %v", err, tt.wantErr)
 			}
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("got %d warnings %q, want %d", len(warnings), warnings, tt.wantWarnings)
+			}
 		})
 	}
 }
 
+func FuzzLoadProcesses(f *testing.F) {
+	for _, seed := range []string{
+		"1,5,0,2\n2,9,3,1\n3,6,3,3",
+		"1,5,0\n2,9,3,1",
+		`1,5,0,2,"build, test"`,
+		`1,5,0,2,"unterminated`,
+		"1,5,0,2,,2\n2,9,3,1,lint,",
+		"1,5,0,2,,6",
+		"1,5,0,2,build,3,nightly,extra",
+		"1,5",
+		"",
+		"\n\n,,,\n",
+		"1,nine,0",
+		"1,9223372036854775807,9223372036854775807,-9223372036854775808",
+		"1,5,9223372036854775807\n2,5,0",
+		"1,9223372036854775807,0\n2,1,0",
+		"1,99999999999999999999,0",
+		"1,-2,0\n2,2,0",
+		"1,5,-1",
+		"1,5,2024-03-01T10:00:30Z,2\n2,9,0001-01-01T00:00:00Z",
+		"1,5,9999-12-31T23:59:59Z\n2,5,0001-01-01T00:00:00Z",
+		"\xff\xfe,\x00,\"\"\"",
+	} {
+		f.Add([]byte(seed), false, false)
+		f.Add([]byte(seed), true, true)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte, skip, timestamps bool) {
+		s := defaultSettings()
+		if skip {
+			s.errorPolicy = errorPolicySkip
+		}
+		if timestamps {
+			s.arrivalFormat = arrivalTimestamp
+		}
+		got, err := loadProcesses(bytes.NewReader(data), s, func(string) {})
+		if err != nil {
+			if got != nil {
+				t.Errorf("loadProcesses() = %v with error %v, want no processes", got, err)
+			}
+			if !errors.Is(err, ErrInvalidRow) {
+				t.Errorf("error = %v, want %v", err, ErrInvalidRow)
+			}
+			return
+		}
+		if got == nil {
+			t.Fatalf("loadProcesses() = nil without an error")
+		}
+
+		// Every process must be schedulable without any time overflowing.
+		var latest, bursts int64
+		for _, p := range got {
+			if p.BurstDuration < 0 || p.ArrivalTime < 0 {
+				t.Errorf("loaded %+v with a negative burst or arrival", p)
+			}
+			if p.Remaining < 0 || p.Remaining > p.BurstDuration || p.Remaining > 0 && p.ArrivalTime != 0 {
+				t.Errorf("loaded %+v with an impossible remaining burst", p)
+			}
+			if p.Line < 1 {
+				t.Errorf("loaded %+v without its line", p)
+			}
+			if p.ArrivalTime > latest {
+				latest = p.ArrivalTime
+			}
+			if p.BurstDuration > math.MaxInt64-bursts || latest > math.MaxInt64-bursts-p.BurstDuration {
+				t.Fatalf("loaded %+v, letting the workload run past the largest int64", p)
+			}
+			bursts += p.BurstDuration
+		}
+	})
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
 func loadFixture(t *testing.T, p ...string) string {
 	b, err := os.ReadFile(path.Join(p...))
 	if err != nil {
@@ -205,3 +583,1295 @@ func Test_openProcessingFile1(t *testing.T) {
 		})
 	}
 }
+
+func Test_firstDrain(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		wantAt    int64
+		wantOK    bool
+	}{
+		{
+			name: "busy, idle, busy again",
+			processes: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 1},
+				{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2},
+				{ProcessID: 3, ArrivalTime: 9, BurstDuration: 3},
+				{ProcessID: 4, ArrivalTime: 14, BurstDuration: 4},
+			},
+			wantAt: 3,
+			wantOK: true,
+		},
+		{
+			name: "only idle before the first arrival",
+			processes: []Process{
+				{ProcessID: 1, ArrivalTime: 4, BurstDuration: 3},
+				{ProcessID: 2, ArrivalTime: 5, BurstDuration: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			for _, sc := range schedulers {
+				at, ok := firstDrain(sc.schedule(tt.processes, defaultSettings()).Gantt)
+				if at != tt.wantAt || ok != tt.wantOK {
+					t.Errorf("%s: firstDrain() = %d, %v, want %d, %v", sc.name, at, ok, tt.wantAt, tt.wantOK)
+				}
+			}
+		})
+	}
+}
+
+func Test_longestIdleGap(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		gantt      []TimeSlice
+		wantStart  int64
+		wantLength int64
+	}{
+		{
+			name: "no idle",
+			gantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 5},
+				{PID: 2, Start: 5, Stop: 14},
+			},
+		},
+		{
+			name: "two gaps, longer second",
+			gantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 3},
+				{PID: 2, Start: 5, Stop: 9},
+				{PID: 3, Start: 15, Stop: 20},
+			},
+			wantStart:  9,
+			wantLength: 6,
+		},
+		{
+			name: "leading gap",
+			gantt: []TimeSlice{
+				{PID: 1, Start: 4, Stop: 6},
+				{PID: 2, Start: 7, Stop: 9},
+			},
+			wantStart:  0,
+			wantLength: 4,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			gotStart, gotLength := longestIdleGap(tt.gantt)
+			if gotStart != tt.wantStart || gotLength != tt.wantLength {
+				t.Errorf("longestIdleGap() = (%d, %d), want (%d, %d)", gotStart, gotLength, tt.wantStart, tt.wantLength)
+			}
+		})
+	}
+}
+
+func Test_roundRobinSchedule_preemptOnArrival(t *testing.T) {
+	t.Parallel()
+	processes := func() []Process {
+		return []Process{
+			{ProcessID: 1, ArrivalTime: 0, BurstDuration: 10, Priority: 5},
+			{ProcessID: 2, ArrivalTime: 2, BurstDuration: 2, Priority: 1},
+		}
+	}
+	tests := []struct {
+		name      string
+		preempt   bool
+		wantGantt []TimeSlice
+	}{
+		{
+			name: "quantum runs to completion",
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 4},
+				{PID: 1, Start: 4, Stop: 8},
+				{PID: 2, Start: 8, Stop: 10},
+				{PID: 1, Start: 10, Stop: 12},
+			},
+		},
+		{
+			name:    "high priority arrival cuts quantum short",
+			preempt: true,
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2},
+				{PID: 2, Start: 2, Stop: 4},
+				{PID: 1, Start: 4, Stop: 8},
+				{PID: 1, Start: 8, Stop: 12},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := defaultSettings()
+			s.preemptOnArrival = tt.preempt
+			got := roundRobinSchedule(processes(), s)
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("roundRobinSchedule() gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+		})
+	}
+}
+
+func Test_roundRobinSchedule_requeueOrder(t *testing.T) {
+	t.Parallel()
+	// P1's first quantum ends at 4, the instant P2 arrives, leaving P1 with
+	// more to do than P2's whole burst.
+	processes := func() []Process {
+		return []Process{
+			{ProcessID: 1, ArrivalTime: 0, BurstDuration: 6},
+			{ProcessID: 2, ArrivalTime: 4, BurstDuration: 1},
+		}
+	}
+	tests := []struct {
+		order     string
+		wantGantt []TimeSlice
+	}{
+		{
+			order: requeueFIFO,
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 4},
+				{PID: 1, Start: 4, Stop: 6},
+				{PID: 2, Start: 6, Stop: 7},
+			},
+		},
+		{
+			order: requeueRemaining,
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 4},
+				{PID: 2, Start: 4, Stop: 5},
+				{PID: 1, Start: 5, Stop: 7},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.order, func(t *testing.T) {
+			t.Parallel()
+			s := defaultSettings()
+			s.rrRequeueOrder = tt.order
+			got := roundRobinSchedule(processes(), s)
+			if !reflect.DeepEqual(got.Gantt, tt.wantGantt) {
+				t.Errorf("roundRobinSchedule() gantt = %v, want %v", got.Gantt, tt.wantGantt)
+			}
+		})
+	}
+}
+
+func Test_outputJSONAll(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+	}
+
+	var w bytes.Buffer
+	if err := outputJSONAll(&w, runSchedulers(processes, defaultSettings())); err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Algorithms []struct {
+			Name    string        `json:"name"`
+			Gantt   []TimeSlice   `json:"gantt"`
+			Rows    []ScheduleRow `json:"rows"`
+			Metrics Metrics       `json:"metrics"`
+		} `json:"algorithms"`
+	}
+	if err := json.Unmarshal(w.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if len(got.Algorithms) != 4 {
+		t.Fatalf("got %d algorithms, want 4", len(got.Algorithms))
+	}
+	for _, a := range got.Algorithms {
+		if len(a.Gantt) == 0 || len(a.Rows) != len(processes) || a.Metrics.Throughput == 0 {
+			t.Errorf("algorithm %q is incomplete: %+v", a.Name, a)
+		}
+	}
+}
+
+func Test_sjfPrioritySchedule_fullTies(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 4, ArrivalTime: 0, BurstDuration: 3, Priority: 1},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 3, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 0, BurstDuration: 3, Priority: 1},
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 3, Priority: 1},
+	}
+
+	got := sjfPrioritySchedule(processes, defaultSettings())
+	want := []int64{1, 2, 3, 4}
+	if len(got.Gantt) != len(want) {
+		t.Fatalf("got %d slices, want %d", len(got.Gantt), len(want))
+	}
+	for i := range want {
+		if got.Gantt[i].PID != want[i] {
+			t.Errorf("slice %d dispatched PID %d, want %d", i, got.Gantt[i].PID, want[i])
+		}
+	}
+}
+
+func Test_negativePriorities(t *testing.T) {
+	t.Parallel()
+	// Lower numbers are more important, so -1 outranks 0, which outranks 5.
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 3, Priority: 5},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 3, Priority: 0},
+		{ProcessID: 3, ArrivalTime: 0, BurstDuration: 3, Priority: -1},
+	}
+
+	got := sjfPrioritySchedule(append([]Process(nil), processes...), defaultSettings())
+	var order []int64
+	for _, slice := range got.Gantt {
+		order = append(order, slice.PID)
+	}
+	if want := []int64{3, 2, 1}; !reflect.DeepEqual(order, want) {
+		t.Errorf("priority dispatched %v, want %v", order, want)
+	}
+
+	// Round-robin preemption ranks them the same way.
+	running := Process{ProcessID: 4, BurstDuration: 8, Priority: 0}
+	pending := []Process{
+		{ProcessID: 1, ArrivalTime: 1, BurstDuration: 3, Priority: 5},
+		{ProcessID: 2, ArrivalTime: 2, BurstDuration: 3, Priority: 0},
+		{ProcessID: 3, ArrivalTime: 3, BurstDuration: 3, Priority: -1},
+	}
+	if i := preemptingArrival(pending, running, 0, 4); i != 2 {
+		t.Errorf("preemptingArrival() = %d, want 2 (priority -1 preempts priority 0)", i)
+	}
+}
+
+func Test_outputWaitIntervals(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 10, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 6, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 3, BurstDuration: 3, Priority: 3},
+	}
+	s := defaultSettings()
+	s.showWaitingIntervals = true
+	r := roundRobinSchedule(append([]Process(nil), processes...), s)
+
+	var w bytes.Buffer
+	outputResult(&w, r, s)
+	out := w.String()
+	section := out[strings.Index(out, "Waiting intervals\n")+len("Waiting intervals\n"):]
+
+	completion := make(map[int64]int64)
+	for _, slice := range r.Gantt {
+		completion[slice.PID] = slice.Stop
+	}
+	span := regexp.MustCompile(`(\d+)-(\d+)`)
+	for _, p := range processes {
+		prefix := fmt.Sprintf("%d: ", p.ProcessID)
+		var line string
+		for _, l := range strings.Split(section, "\n") {
+			if strings.HasPrefix(l, prefix) {
+				line = l
+			}
+		}
+		if line == "" {
+			t.Fatalf("no waiting intervals printed for PID %d:\n%s", p.ProcessID, section)
+		}
+
+		var total int64
+		for _, m := range span.FindAllStringSubmatch(strings.TrimPrefix(line, prefix), -1) {
+			start, _ := strconv.ParseInt(m[1], 10, 64)
+			stop, _ := strconv.ParseInt(m[2], 10, 64)
+			total += stop - start
+		}
+		if want := completion[p.ProcessID] - p.ArrivalTime - p.BurstDuration; total != want {
+			t.Errorf("PID %d waiting intervals total %d, want accumulated wait %d (%q)", p.ProcessID, total, want, line)
+		}
+	}
+}
+
+func Test_checkMonotonicIDs(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		ids     []int64
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name: "in order",
+			ids:  []int64{1, 2, 3},
+		},
+		{
+			name:    "out of order",
+			ids:     []int64{1, 3, 2},
+			wantErr: ErrInvalidRow,
+			wantMsg: "row 4 has ID 3",
+		},
+		{
+			name:    "gap",
+			ids:     []int64{1, 2, 4},
+			wantErr: ErrInvalidRow,
+			wantMsg: "row 6 has ID 4",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			// Each process follows a comment line, so its file line is
+			// twice its position.
+			processes := make([]Process, len(tt.ids))
+			for i, id := range tt.ids {
+				processes[i].ProcessID = id
+				processes[i].Line = 2 * (i + 1)
+			}
+			err := checkMonotonicIDs(processes)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("checkMonotonicIDs() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("checkMonotonicIDs() error = %q, want it to mention %q", err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func Test_contentionWait(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		run            func([]Process, settings) ScheduleResult
+		processes      []Process
+		pid            int64
+		wantWait       int64
+		wantContention int64
+	}{
+		{
+			name: "lone late arrival never waits",
+			run:  sjfSchedule,
+			processes: []Process{
+				{ProcessID: 1, ArrivalTime: 3, BurstDuration: 4, Priority: 1},
+			},
+			pid:            1,
+			wantWait:       0,
+			wantContention: 0,
+		},
+		{
+			name: "queued behind another job",
+			run:  fcfsSchedule,
+			processes: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 1},
+				{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2, Priority: 1},
+			},
+			pid:            2,
+			wantWait:       4,
+			wantContention: 4,
+		},
+		{
+			// sjf holds 1 back for the shorter 2, idling from 0 to 3: only
+			// the 2 ticks 2 runs count as contention.
+			name: "idle time is not contention",
+			run:  sjfSchedule,
+			processes: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 1},
+				{ProcessID: 2, ArrivalTime: 3, BurstDuration: 2, Priority: 1},
+			},
+			pid:            1,
+			wantWait:       5,
+			wantContention: 2,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := scheduler{run: tt.run}.schedule(tt.processes, defaultSettings())
+			var row ScheduleRow
+			for _, row = range r.Rows {
+				if row.ProcessID == tt.pid {
+					break
+				}
+			}
+			if row.ProcessID != tt.pid {
+				t.Fatalf("no row for PID %d in %+v", tt.pid, r.Rows)
+			}
+			if row.Wait != tt.wantWait {
+				t.Errorf("standard wait = %d, want %d", row.Wait, tt.wantWait)
+			}
+			if row.ContentionWait != tt.wantContention {
+				t.Errorf("contention wait = %d, want %d", row.ContentionWait, tt.wantContention)
+			}
+		})
+	}
+}
+
+func Test_firstStart_roundRobin(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 12, Priority: 1},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 12, Priority: 1},
+	}
+	s := defaultSettings()
+	r := scheduler{run: roundRobinSchedule}.schedule(processes, s)
+
+	want := map[int64]int64{1: 0, 2: 4}
+	for _, row := range r.Rows {
+		if row.FirstStart == nil || *row.FirstStart != want[row.ProcessID] {
+			t.Fatalf("PID %d first start = %v, want %d", row.ProcessID, row.FirstStart, want[row.ProcessID])
+		}
+		if row.Exit-*row.FirstStart < 3*s.quantum {
+			t.Errorf("PID %d first start %d and exit %d are less than three quanta apart", row.ProcessID, *row.FirstStart, row.Exit)
+		}
+	}
+}
+
+func Test_formatAverage(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		v         float64
+		precision int
+		rounding  string
+		want      string
+	}{
+		{name: "nearest", v: 11.0 / 3, precision: 0, rounding: roundNearest, want: "4"},
+		{name: "floor", v: 11.0 / 3, precision: 0, rounding: roundFloor, want: "3"},
+		{name: "ceil", v: 10.0 / 3, precision: 0, rounding: roundCeil, want: "4"},
+		{name: "nearest rounds down", v: 10.0 / 3, precision: 0, rounding: roundNearest, want: "3"},
+		{name: "default precision", v: 10.0 / 3, precision: 2, rounding: roundNearest, want: "3.33"},
+		{name: "floor keeps exact decimals", v: 0.29, precision: 2, rounding: roundFloor, want: "0.29"},
+		{name: "ceil keeps exact decimals", v: 0.15, precision: 2, rounding: roundCeil, want: "0.15"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := defaultSettings()
+			s.precision = tt.precision
+			s.rounding = tt.rounding
+			if got := formatAverage(tt.v, s); got != tt.want {
+				t.Errorf("formatAverage(%v) = %q, want %q", tt.v, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_filterIDs(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5},
+		{ProcessID: 2, BurstDuration: 9},
+		{ProcessID: 3, BurstDuration: 6},
+		{ProcessID: 4, BurstDuration: 2},
+		{ProcessID: 5, BurstDuration: 7},
+	}
+	tests := []struct {
+		name    string
+		ids     []int64
+		want    []int64
+		wantErr error
+	}{
+		{
+			name: "subset keeps file order",
+			ids:  []int64{5, 1, 3},
+			want: []int64{1, 3, 5},
+		},
+		{
+			name:    "missing ID",
+			ids:     []int64{1, 9},
+			wantErr: ErrInvalidArgs,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := filterIDs(processes, tt.ids)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("filterIDs() error = %v, want %v", err, tt.wantErr)
+			}
+			var gotIDs []int64
+			for _, p := range got {
+				gotIDs = append(gotIDs, p.ProcessID)
+			}
+			if !reflect.DeepEqual(gotIDs, tt.want) {
+				t.Errorf("filterIDs() IDs = %v, want %v", gotIDs, tt.want)
+			}
+			if err != nil {
+				return
+			}
+
+			for _, r := range runSchedulers(got, defaultSettings()) {
+				for _, row := range r.Rows {
+					if _, ok := wanted(tt.ids)[row.ProcessID]; !ok {
+						t.Errorf("%s scheduled unselected PID %d", r.Name, row.ProcessID)
+					}
+				}
+			}
+		})
+	}
+}
+
+func wanted(ids []int64) map[int64]struct{} {
+	set := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+
+	return set
+}
+
+func Test_outputResult_maxGanttSlices(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+	}
+	full := defaultSettings()
+	capped := defaultSettings()
+	capped.maxGanttSlices = 3
+
+	r := scheduler{title: "Round-robin", run: roundRobinSchedule}.schedule(processes, full)
+	var fullOut, cappedOut bytes.Buffer
+	outputResult(&fullOut, r, full)
+	outputResult(&cappedOut, r, capped)
+
+	if want := fmt.Sprintf("… (truncated, %d more)", len(r.Gantt)-3); !strings.Contains(cappedOut.String(), want) {
+		t.Errorf("capped output lacks %q:\n%s", want, cappedOut.String())
+	}
+	if strings.Contains(fullOut.String(), "truncated") {
+		t.Errorf("uncapped output is truncated:\n%s", fullOut.String())
+	}
+
+	gantt := func(out string) string {
+		lines := strings.Split(out, "\n")
+		return lines[4]
+	}
+	if got := strings.Count(gantt(cappedOut.String()), "|") - 1; got != 3 {
+		t.Errorf("capped chart draws %d slices, want 3", got)
+	}
+
+	table := func(out string) string {
+		return out[strings.Index(out, "Schedule table"):]
+	}
+	if table(cappedOut.String()) != table(fullOut.String()) {
+		t.Errorf("capping the chart changed the table:\n%s\nwant\n%s", table(cappedOut.String()), table(fullOut.String()))
+	}
+}
+
+func Test_zeroBurstMetrics(t *testing.T) {
+	t.Parallel()
+	workloads := map[string][]Process{
+		"all at 0": {
+			{ProcessID: 1, ArrivalTime: 0, BurstDuration: 0, Priority: 1},
+			{ProcessID: 2, ArrivalTime: 0, BurstDuration: 0, Priority: 2},
+		},
+		"late arrival": {
+			{ProcessID: 1, ArrivalTime: 0, BurstDuration: 0, Priority: 1},
+			{ProcessID: 2, ArrivalTime: 3, BurstDuration: 0, Priority: 2},
+		},
+	}
+	s := defaultSettings()
+	s.perProcess = true
+
+	for name, processes := range workloads {
+		for _, sc := range schedulers {
+			name, processes, sc := name, processes, sc
+			t.Run(name+"/"+sc.name, func(t *testing.T) {
+				t.Parallel()
+				testZeroBurstMetrics(t, sc.schedule(append([]Process(nil), processes...), s), s)
+			})
+		}
+	}
+}
+
+func Test_allZeroBursts(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		bursts []int64
+		want   bool
+	}{
+		{name: "no processes"},
+		{name: "all zero", bursts: []int64{0, 0}, want: true},
+		{name: "mean of zero", bursts: []int64{-2, 2}},
+		{name: "one nonzero", bursts: []int64{0, 3}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processes := make([]Process, len(tt.bursts))
+			for i, burst := range tt.bursts {
+				processes[i] = Process{ProcessID: int64(i + 1), BurstDuration: burst}
+			}
+			if got := allZeroBursts(processes); got != tt.want {
+				t.Errorf("allZeroBursts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func testZeroBurstMetrics(t *testing.T, r ScheduleResult, s settings) {
+	t.Helper()
+	if r.Metrics.Throughput != 0 {
+		t.Errorf("throughput = %v, want 0", r.Metrics.Throughput)
+	}
+	for name, v := range map[string]float64{
+		"wait":       r.Metrics.AverageWait,
+		"contention": r.Metrics.AverageContentionWait,
+		"turnaround": r.Metrics.AverageTurnaround,
+		"throughput": r.Metrics.Throughput,
+	} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			t.Errorf("average %s = %v, want a finite number", name, v)
+		}
+	}
+	for _, summary := range r.Summary {
+		if math.IsNaN(summary.WeightedTurnaround) || math.IsInf(summary.WeightedTurnaround, 0) {
+			t.Errorf("process %d weighted turnaround = %v", summary.ProcessID, summary.WeightedTurnaround)
+		}
+	}
+
+	var out bytes.Buffer
+	outputResult(&out, r, s)
+	if text := strings.ToUpper(out.String()); strings.Contains(text, "INF") || strings.Contains(text, "NAN") {
+		t.Errorf("report shows a non-finite number:\n%s", out.String())
+	}
+}
+
+func Test_outputGantt_wideLabels(t *testing.T) {
+	t.Parallel()
+	gantt := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 2},
+		{PID: 2, Start: 2, Stop: 12},
+		{PID: 3, Start: 12, Stop: 13},
+	}
+	labels := map[int64]string{1: "café", 2: "編譯"}
+
+	tests := []struct {
+		name         string
+		proportional bool
+		want         string
+	}{
+		{
+			name: "fixed",
+			want: "Gantt schedule\n" +
+				"|  café  |  編譯  |   3   |\n" +
+				"0\t2\t12\t13\n\n",
+		},
+		{
+			name:         "proportional",
+			proportional: true,
+			want: "Gantt schedule\n" +
+				"| café |   編譯   | 3 |\n" +
+				"0      2          12  13\n\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := defaultSettings()
+			s.ganttProportional = tt.proportional
+
+			var out bytes.Buffer
+			outputGantt(&out, gantt, labels, s)
+			if out.String() != tt.want {
+				t.Errorf("outputGantt() =\n%s\nwant\n%s", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func Test_outputGantt_proportional(t *testing.T) {
+	t.Parallel()
+	s := defaultSettings()
+	s.ganttProportional = true
+	gantt := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 4},
+		{PID: 2, Start: 4, Stop: 12},
+		{PID: 3, Start: 14, Stop: 15},
+	}
+
+	var out bytes.Buffer
+	outputGantt(&out, gantt, nil, s)
+
+	want := "Gantt schedule\n" +
+		"| 1  |   2    |  | 3 |\n" +
+		"0    4        12 14  15\n\n"
+	if out.String() != want {
+		t.Fatalf("outputGantt() =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	cells := strings.Split(strings.Split(out.String(), "\n")[1], "|")
+	if short, long := len(cells[1]), len(cells[2]); long != 2*short {
+		t.Errorf("duration-8 cell is %d wide, want twice the duration-4 cell's %d", long, short)
+	}
+}
+
+func Test_outputSchedule_compactTable(t *testing.T) {
+	t.Parallel()
+	rows := []ScheduleRow{
+		{ProcessID: 1, Priority: 2, Burst: 5, Exit: 5, Turnaround: 5},
+		{ProcessID: 2, Priority: 1, Burst: 9, Arrival: 3, FirstStart: int64Ptr(5), Wait: 2, ContentionWait: 2, Turnaround: 11, Exit: 14},
+	}
+	metrics := Metrics{AverageWait: 1, AverageContentionWait: 1, AverageTurnaround: 8, Throughput: 0.14}
+
+	tests := []struct {
+		name        string
+		compact     bool
+		wantBorders bool
+	}{
+		{name: "default", wantBorders: true},
+		{name: "compact", compact: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := defaultSettings()
+			s.compactTable = tt.compact
+			var w bytes.Buffer
+			outputSchedule(&w, rows, metrics, s)
+			out := w.String()
+			if got := strings.ContainsAny(out, "+|"); got != tt.wantBorders {
+				t.Errorf("borders present = %v, want %v:\n%s", got, tt.wantBorders, out)
+			}
+			if !strings.Contains(out, "14") || !strings.Contains(out, "0.14/T") {
+				t.Errorf("table lost its contents:\n%s", out)
+			}
+		})
+	}
+}
+
+func Test_dumpProcesses_arrivalOffset(t *testing.T) {
+	t.Parallel()
+	f, err := os.CreateTemp(t.TempDir(), "*.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("1,5,0,2,lint\n2,9,3,1,\"build, test\"\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := defaultSettings()
+	s.arrivalOffset = 10
+	processes, err := readProcesses(s, warner{w: io.Discard}, "binary_name", f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var w bytes.Buffer
+	if err := dumpProcesses(&w, processes); err != nil {
+		t.Fatal(err)
+	}
+	if want := "1,5,10,2,lint\n2,9,13,1,\"build, test\"\n"; w.String() != want {
+		t.Errorf("dumpProcesses() = %q, want %q", w.String(), want)
+	}
+}
+
+func Test_quantumFromFraction(t *testing.T) {
+	t.Parallel()
+	// Mean burst is (5 + 9 + 6 + 2) / 4 = 5.5.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5},
+		{ProcessID: 2, BurstDuration: 9},
+		{ProcessID: 3, BurstDuration: 6},
+		{ProcessID: 4, BurstDuration: 2},
+	}
+	tests := []struct {
+		name string
+		frac float64
+		want int64
+	}{
+		{name: "half rounds 2.75 up", frac: 0.5, want: 3},
+		{name: "whole rounds 5.5 up", frac: 1, want: 6},
+		{name: "double", frac: 2, want: 11},
+		{name: "never below one tick", frac: 0.01, want: 1},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := quantumFromFraction(processes, tt.frac)
+			if got != tt.want {
+				t.Errorf("quantumFromFraction(%v) = %d, want %d", tt.frac, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_computeMetrics_sameGanttAcrossSchedulers(t *testing.T) {
+	t.Parallel()
+	// Every scheduler dispatches this workload identically: 1 runs first, then
+	// 2 and 3 are tied on arrival with 2 ahead by burst, priority and PID.
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 2, Priority: 1},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 3, Priority: 2},
+		{ProcessID: 3, ArrivalTime: 1, BurstDuration: 4, Priority: 3},
+	}
+	wantGantt := []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 5}, {PID: 3, Start: 5, Stop: 9}}
+	want := Metrics{
+		AverageWait:           5.0 / 3,
+		AverageContentionWait: 5.0 / 3,
+		AverageTurnaround:     14.0 / 3,
+		Throughput:            3.0 / 9,
+		AverageQueueLength:    5.0 / 9,
+	}
+	if got := computeMetrics(processes, wantGantt); got != want {
+		t.Fatalf("computeMetrics() = %+v, want %+v", got, want)
+	}
+
+	for _, sc := range schedulers {
+		sc := sc
+		t.Run(sc.name, func(t *testing.T) {
+			t.Parallel()
+			r := sc.schedule(append([]Process(nil), processes...), defaultSettings())
+			if !reflect.DeepEqual(r.Gantt, wantGantt) {
+				t.Fatalf("Gantt = %v, want %v", r.Gantt, wantGantt)
+			}
+			if r.Metrics != want {
+				t.Errorf("Metrics = %+v, want %+v", r.Metrics, want)
+			}
+		})
+	}
+}
+
+func Test_computeMetrics_averageQueueLength(t *testing.T) {
+	t.Parallel()
+	// Under FCFS: 1 runs 0-4, 2 runs 4-7, 3 runs 7-9, then the CPU idles
+	// until 4 runs 12-13. The ready queue holds 2 over 1-4 and 3 over 2-7,
+	// so it is 0 long for 1 tick, 1 for 1, 2 for 2, 1 for 3 and 0 for the
+	// last 6: (1 + 4 + 3) / 13.
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 3},
+		{ProcessID: 3, ArrivalTime: 2, BurstDuration: 2},
+		{ProcessID: 4, ArrivalTime: 12, BurstDuration: 1},
+	}
+	r := scheduler{name: "fcfs", run: fcfsSchedule}.schedule(processes, defaultSettings())
+	if got, want := r.Metrics.AverageQueueLength, 8.0/13; math.Abs(got-want) > 1e-9 {
+		t.Errorf("AverageQueueLength = %v, want %v", got, want)
+	}
+}
+
+func Test_outputAlgorithmHelp(t *testing.T) {
+	t.Parallel()
+	var w bytes.Buffer
+	outputAlgorithmHelp(&w)
+	out := w.String()
+	for _, sc := range schedulers {
+		if !strings.Contains(out, "  "+sc.name+" ") || !strings.Contains(out, sc.description) {
+			t.Errorf("help output lacks %s:\n%s", sc.name, out)
+		}
+		for _, param := range sc.params {
+			if !strings.Contains(out, param) {
+				t.Errorf("help output lacks %s's parameter %s:\n%s", sc.name, param, out)
+			}
+		}
+	}
+}
+
+func Test_capSchedule_avgCompletedOnly(t *testing.T) {
+	t.Parallel()
+	// Capped at t=6, 1 has finished (0-4) but 2 has only run 4-6 of its 6
+	// ticks, and 3 has not arrived.
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 6},
+		{ProcessID: 3, ArrivalTime: 7, BurstDuration: 1},
+	}
+	tests := []struct {
+		name           string
+		completedOnly  bool
+		wantWait       float64
+		wantTurnaround float64
+	}{
+		// 1 waited 0 over 4 ticks; 2 waited 3 over 5 so far.
+		{name: "all arrived", wantWait: 1.5, wantTurnaround: 4.5},
+		{name: "completed only", completedOnly: true, wantWait: 0, wantTurnaround: 4},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := defaultSettings()
+			s.maxTime = 6
+			s.avgCompletedOnly = tt.completedOnly
+			sc, _ := lookupScheduler("fcfs")
+			r := sc.schedule(append([]Process(nil), processes...), s)
+
+			if r.Metrics.AverageWait != tt.wantWait || r.Metrics.AverageTurnaround != tt.wantTurnaround {
+				t.Errorf("averages wait %v, turnaround %v; want %v, %v",
+					r.Metrics.AverageWait, r.Metrics.AverageTurnaround, tt.wantWait, tt.wantTurnaround)
+			}
+			if want := 1.0 / 6; r.Metrics.Throughput != want {
+				t.Errorf("throughput %v, want %v", r.Metrics.Throughput, want)
+			}
+			if len(r.Rows) != 2 || r.Rows[0].Unfinished || !r.Rows[1].Unfinished {
+				t.Errorf("rows = %+v, want 1 finished and 2 unfinished", r.Rows)
+			}
+			if want := (TimeSlice{PID: 2, Start: 4, Stop: 6}); r.Gantt[len(r.Gantt)-1] != want {
+				t.Errorf("last slice = %v, want %v", r.Gantt[len(r.Gantt)-1], want)
+			}
+		})
+	}
+}
+
+func Test_capSchedule_neverDispatched(t *testing.T) {
+	t.Parallel()
+	// Capped at t=5, 1 is still running and 2 has waited 4 ticks behind it
+	// without ever being dispatched.
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 10, Priority: 1},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2, Priority: 1},
+	}
+	s := defaultSettings()
+	s.maxTime = 5
+	sc, _ := lookupScheduler("fcfs")
+	r := sc.schedule(processes, s)
+
+	want := []ScheduleRow{
+		{ProcessID: 1, Priority: 1, Burst: 10, FirstStart: int64Ptr(0), Turnaround: 5, Unfinished: true},
+		{ProcessID: 2, Priority: 1, Burst: 2, Arrival: 1, Wait: 4, ContentionWait: 4, Turnaround: 4, Unfinished: true},
+	}
+	if !reflect.DeepEqual(r.Rows, want) {
+		t.Errorf("rows = %+v, want %+v", r.Rows, want)
+	}
+	if r.Metrics.AverageContentionWait != 2 {
+		t.Errorf("average contention = %v, want 2", r.Metrics.AverageContentionWait)
+	}
+
+	var table bytes.Buffer
+	outputSchedule(&table, r.Rows, r.Metrics, s)
+	if !regexp.MustCompile(`\|\s+2\s+\|\s+1\s+\|\s+2\s+\|\s+1\s+\|\s+-\s+\|\s+4\s+\|\s+4\s+\|`).MatchString(table.String()) {
+		t.Errorf("table does not show PID 2's first start as -:\n%s", table.String())
+	}
+	b, err := json.Marshal(r.Rows[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "firstStart") {
+		t.Errorf("JSON row %s has a first start", b)
+	}
+}
+
+func Test_resumePartial(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Remaining: 2},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 3},
+	}
+	for _, sc := range schedulers {
+		sc := sc
+		t.Run(sc.name, func(t *testing.T) {
+			t.Parallel()
+			r := sc.schedule(append([]Process(nil), processes...), defaultSettings())
+			if want := (TimeSlice{PID: 1, Start: 0, Stop: 2}); r.Gantt[0] != want {
+				t.Errorf("first slice = %v, want %v", r.Gantt[0], want)
+			}
+		})
+	}
+
+	if processes[0].BurstDuration != 5 || processes[0].Remaining != 2 {
+		t.Errorf("resumePartial() modified its input: %+v", processes[0])
+	}
+}
+
+func Test_queueCap(t *testing.T) {
+	t.Parallel()
+	// 1 runs from t=0 while 2 and 3 arrive at t=1 and t=2.
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 6},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2},
+		{ProcessID: 3, ArrivalTime: 2, BurstDuration: 2},
+	}
+	tests := []struct {
+		name        string
+		algo        string
+		queueCap    int
+		overflow    string
+		wantDropped []int64
+		wantGantt   []TimeSlice
+	}{
+		{
+			// 2 is still queued when 3 arrives.
+			name:        "fcfs drop",
+			algo:        "fcfs",
+			queueCap:    1,
+			overflow:    overflowDrop,
+			wantDropped: []int64{3},
+			wantGantt:   []TimeSlice{{PID: 1, Start: 0, Stop: 6}, {PID: 2, Start: 6, Stop: 8}},
+		},
+		{
+			name:     "fcfs delay",
+			algo:     "fcfs",
+			queueCap: 1,
+			overflow: overflowDelay,
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 6}, {PID: 2, Start: 6, Stop: 8}, {PID: 3, Start: 8, Stop: 10},
+			},
+		},
+		{
+			// At t=4 the preempted 1 rejoins the queue ahead of the
+			// arrivals, leaving room for 2 but not 3.
+			name:        "rr drop",
+			algo:        "rr",
+			queueCap:    2,
+			overflow:    overflowDrop,
+			wantDropped: []int64{3},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 4}, {PID: 1, Start: 4, Stop: 6}, {PID: 2, Start: 6, Stop: 8},
+			},
+		},
+		{
+			// 3 is held back while 1 and 2 fill the queue, getting in at
+			// t=6 once 1 has been dispatched.
+			name:     "rr delay",
+			algo:     "rr",
+			queueCap: 2,
+			overflow: overflowDelay,
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 4}, {PID: 1, Start: 4, Stop: 6},
+				{PID: 2, Start: 6, Stop: 8}, {PID: 3, Start: 8, Stop: 10},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := defaultSettings()
+			s.queueCap = tt.queueCap
+			s.overflow = tt.overflow
+			sc, _ := lookupScheduler(tt.algo)
+			r := sc.schedule(append([]Process(nil), processes...), s)
+			if !reflect.DeepEqual(r.Dropped, tt.wantDropped) {
+				t.Errorf("Dropped = %v, want %v", r.Dropped, tt.wantDropped)
+			}
+			if !reflect.DeepEqual(r.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", r.Gantt, tt.wantGantt)
+			}
+		})
+	}
+}
+
+func Test_outputSchedule_noPriority(t *testing.T) {
+	t.Parallel()
+	processes, err := loadProcesses(strings.NewReader("1,5,0\n2,9,3\n3,6,6\n"), defaultSettings(), warner{w: io.Discard}.of(warnSkippedRow))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"fcfs", "sjf"} {
+		sc, _ := lookupScheduler(name)
+		r := sc.schedule(append([]Process(nil), processes...), defaultSettings())
+		var w bytes.Buffer
+		outputSchedule(&w, r.Rows, r.Metrics, defaultSettings())
+		if got := strings.Count(w.String(), "n/a"); got != len(processes) {
+			t.Errorf("%s table shows n/a %d times, want once per row:\n%s", name, got, w.String())
+		}
+	}
+
+	var w bytes.Buffer
+	if err := dumpProcesses(&w, processes); err != nil {
+		t.Fatal(err)
+	}
+	if want := "1,5,0\n2,9,3\n3,6,6\n"; w.String() != want {
+		t.Errorf("dumpProcesses() = %q, want %q", w.String(), want)
+	}
+}
+
+func Test_dumpProcesses_roundTrip(t *testing.T) {
+	t.Parallel()
+	// As -db loads them: P2 and P3 have no priority of their own, and P2
+	// still has a label to write after it.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5, Priority: 3},
+		{ProcessID: 2, BurstDuration: 9, ArrivalTime: 3, NoPriority: true, Label: "build"},
+		{ProcessID: 3, BurstDuration: 6, ArrivalTime: 6, NoPriority: true},
+	}
+	defaultPriorities(processes, missingLowest)
+
+	var w bytes.Buffer
+	if err := dumpProcesses(&w, processes); err != nil {
+		t.Fatal(err)
+	}
+	if want := "1,5,0,3\n2,9,3,4,build\n3,6,6,4\n"; w.String() != want {
+		t.Errorf("dumpProcesses() = %q, want %q", w.String(), want)
+	}
+
+	reloaded, err := loadProcesses(&w, defaultSettings(), warner{w: io.Discard}.of(warnSkippedRow))
+	if err != nil {
+		t.Fatalf("reloading the dump: %v", err)
+	}
+	if len(reloaded) != len(processes) {
+		t.Fatalf("reloaded %d processes, want %d", len(reloaded), len(processes))
+	}
+	for i, p := range reloaded {
+		want := processes[i]
+		if p.ProcessID != want.ProcessID || p.BurstDuration != want.BurstDuration ||
+			p.ArrivalTime != want.ArrivalTime || p.Priority != want.Priority || p.Label != want.Label {
+			t.Errorf("reloaded %+v, want %+v", p, want)
+		}
+	}
+}
+
+func Test_run_keepsOutputOnError(t *testing.T) {
+	t.Parallel()
+	// -sjf-delta fails on staggered arrivals, but only after every table
+	// has been written, into a buffer far from full.
+	var w bytes.Buffer
+	err := runBuffered(&w, "binary_name", "-sjf-delta", "-algo", "fcfs", "example_processes.csv")
+	if !errors.Is(err, ErrInvalidArgs) {
+		t.Fatalf("runBuffered() error = %v, want %v", err, ErrInvalidArgs)
+	}
+	if !strings.Contains(w.String(), "First-come, first-serve") {
+		t.Errorf("runBuffered() dropped the output written before the error:\n%s", w.String())
+	}
+	if w.Len() >= 4096 {
+		t.Errorf("wrote %d bytes, enough to fill the buffer, so a missing flush would go unnoticed", w.Len())
+	}
+}
+
+func Test_run_algoSetsOrder(t *testing.T) {
+	t.Parallel()
+	order := []string{"rr", "fcfs", "priority", "sjf"}
+	tests := []struct {
+		name string
+		args []string
+		// mark finds a title where it starts a section or a table row.
+		mark func(title string) string
+	}{
+		{
+			name: "report sections",
+			mark: func(title string) string { return " " + title + "\n" },
+		},
+		{
+			name: "comparison rows",
+			args: []string{"-summary-table-only"},
+			mark: func(title string) string { return "| " + title + " " },
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			args := append([]string{"binary_name", "-algo", strings.Join(order, ",")}, tt.args...)
+			var w bytes.Buffer
+			if err := run(&w, append(args, "example_processes.csv")...); err != nil {
+				t.Fatalf("run() error = %v", err)
+			}
+			out := w.String()
+
+			last := -1
+			for _, name := range order {
+				sc, _ := lookupScheduler(name)
+				at := strings.Index(out, tt.mark(sc.title))
+				if at <= last {
+					t.Fatalf("%s is at %d, want it after %d:\n%s", sc.title, at, last, out)
+				}
+				last = at
+			}
+		})
+	}
+}
+
+func BenchmarkOutputResult(b *testing.B) {
+	r := scheduler{title: "Round-robin", run: roundRobinSchedule}.
+		schedule(generateWorkload(workloadRRWorst, 200, defaultQuantum), defaultSettings())
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { _ = devNull.Close() })
+
+	b.Run("unbuffered", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			outputResult(devNull, r, defaultSettings())
+		}
+	})
+	b.Run("buffered", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			w := bufio.NewWriter(devNull)
+			outputResult(w, r, defaultSettings())
+			if err := w.Flush(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func Test_schedule_singleProcess(t *testing.T) {
+	t.Parallel()
+	// Every scheduler gives a lone process the same rows and metrics, and
+	// runs it from arrival to exit without a break, but round-robin still
+	// ends its quantum and dispatches it again.
+	tests := []struct {
+		name    string
+		process Process
+		wantRR  []TimeSlice
+	}{
+		{
+			name:    "arrives at 0",
+			process: Process{ProcessID: 1, ArrivalTime: 0, BurstDuration: 7, Priority: 2},
+			wantRR:  []TimeSlice{{PID: 1, Start: 0, Stop: 4}, {PID: 1, Start: 4, Stop: 7}},
+		},
+		{
+			name:    "arrives late",
+			process: Process{ProcessID: 1, ArrivalTime: 3, BurstDuration: 7, Priority: 2},
+			wantRR:  []TimeSlice{{PID: 1, Start: 3, Stop: 7}, {PID: 1, Start: 7, Stop: 10}},
+		},
+		{
+			name:    "shorter than a quantum",
+			process: Process{ProcessID: 1, ArrivalTime: 0, BurstDuration: 2, Priority: 2},
+			wantRR:  []TimeSlice{{PID: 1, Start: 0, Stop: 2}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			p := tt.process
+			exit := p.ArrivalTime + p.BurstDuration
+			wantRow := ScheduleRow{
+				ProcessID:  p.ProcessID,
+				Priority:   p.Priority,
+				Burst:      p.BurstDuration,
+				Arrival:    p.ArrivalTime,
+				FirstStart: int64Ptr(p.ArrivalTime),
+				Turnaround: p.BurstDuration,
+				Exit:       exit,
+			}
+			wantMetrics := Metrics{
+				AverageTurnaround: float64(p.BurstDuration),
+				Throughput:        1 / float64(exit),
+			}
+			for _, sc := range schedulers {
+				r := sc.schedule([]Process{p}, defaultSettings())
+				if len(r.Rows) != 1 || !reflect.DeepEqual(r.Rows[0], wantRow) {
+					t.Errorf("%s rows = %+v, want [%+v]", sc.name, r.Rows, wantRow)
+				}
+				if r.Metrics != wantMetrics {
+					t.Errorf("%s metrics = %+v, want %+v", sc.name, r.Metrics, wantMetrics)
+				}
+				wantGantt := []TimeSlice{{PID: p.ProcessID, Start: p.ArrivalTime, Stop: exit}}
+				if sc.name == "rr" {
+					wantGantt = tt.wantRR
+				}
+				if !reflect.DeepEqual(r.Gantt, wantGantt) {
+					t.Errorf("%s Gantt = %v, want %v", sc.name, r.Gantt, wantGantt)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// batchAverageWait is the exact average wait of running bursts back to back
+// in the given order, all having arrived at 0: the i-th of n bursts holds up
+// the n-1-i behind it.
+func batchAverageWait(bursts []int64) float64 {
+	var total int64
+	for i, b := range bursts {
+		total += int64(len(bursts)-1-i) * b
+	}
+
+	return float64(total) / float64(len(bursts))
+}
+
+// batchBursts returns the bursts of a workload that must all arrive at 0, for
+// the named analysis.
+func batchBursts(processes []Process, analysis string) ([]int64, error) {
+	bursts := make([]int64, len(processes))
+	for i, p := range processes {
+		if p.ArrivalTime != 0 {
+			return nil, fmt.Errorf("%w: %s needs every arrival at 0, PID %d arrives at %d",
+				ErrInvalidArgs, analysis, p.ProcessID, p.ArrivalTime)
+		}
+		bursts[i] = p.BurstDuration
+	}
+
+	return bursts, nil
+}
+
+// sjfDelta returns how much shortest-job-first lowers the average wait below
+// first-come, first-serve for a batch that all arrives at 0, both from the
+// closed form and from running the two schedulers. SJF is provably optimal
+// there, so the reduction is never negative.
+func sjfDelta(processes []Process, s settings) (theory, simulated float64, err error) {
+	bursts, err := batchBursts(processes, "the SJF/FCFS comparison")
+	if err != nil {
+		return 0, 0, err
+	}
+	fcfs := batchAverageWait(bursts)
+	sort.Slice(bursts, func(i, j int) bool { return bursts[i] < bursts[j] })
+	theory = fcfs - batchAverageWait(bursts)
+
+	run := func(name string) ScheduleResult {
+		sc, _ := lookupScheduler(name)
+		return sc.schedule(append([]Process(nil), processes...), s)
+	}
+	simulated = run("fcfs").Metrics.AverageWait - run("sjf").Metrics.AverageWait
+	if formatAverage(theory, s) != formatAverage(simulated, s) {
+		return theory, simulated, fmt.Errorf("%w: SJF cuts the average wait by %s in theory but %s in simulation",
+			ErrInconsistentSchedule, formatAverage(theory, s), formatAverage(simulated, s))
+	}
+
+	return theory, simulated, nil
+}
+
+// outputSJFDelta reports sjfDelta for the workload.
+func outputSJFDelta(w io.Writer, processes []Process, s settings) error {
+	theory, simulated, err := sjfDelta(processes, s)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(w, "SJF lowers the average wait below FCFS by %s (simulated: %s)\n",
+		formatAverage(theory, s), formatAverage(simulated, s))
+
+	return nil
+}
+
+// convoyPenalty measures the convoy effect on a batch that all arrives at 0:
+// the average wait with the largest burst run first (the rest keeping their
+// order) against the optimal, shortest-first order. The difference is the
+// wait caused purely by ordering.
+func convoyPenalty(processes []Process) (convoy, optimal float64, err error) {
+	bursts, err := batchBursts(processes, "the convoy analysis")
+	if err != nil || len(bursts) == 0 {
+		return 0, 0, err
+	}
+
+	largest := 0
+	for i := range bursts {
+		if bursts[i] > bursts[largest] {
+			largest = i
+		}
+	}
+	ordered := append([]int64{bursts[largest]}, bursts[:largest]...)
+	convoy = batchAverageWait(append(ordered, bursts[largest+1:]...))
+
+	sort.Slice(bursts, func(i, j int) bool { return bursts[i] < bursts[j] })
+
+	return convoy, batchAverageWait(bursts), nil
+}
+
+// outputConvoyAnalysis reports convoyPenalty for the workload.
+func outputConvoyAnalysis(w io.Writer, processes []Process, s settings) error {
+	convoy, optimal, err := convoyPenalty(processes)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(w, "Convoy effect: average wait %s with the largest job first, %s shortest first; "+
+		"ordering costs %s\n", formatAverage(convoy, s), formatAverage(optimal, s), formatAverage(convoy-optimal, s))
+
+	return nil
+}
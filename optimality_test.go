@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_sjfDelta(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		want      float64
+		wantErr   error
+	}{
+		{
+			// FCFS waits 0, 24 and 27 (average 17); SJF waits 0, 3 and 6
+			// (average 3).
+			name: "textbook 24, 3, 3",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 24},
+				{ProcessID: 2, BurstDuration: 3},
+				{ProcessID: 3, BurstDuration: 3},
+			},
+			want: 14,
+		},
+		{
+			name: "already shortest first",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 3},
+				{ProcessID: 2, BurstDuration: 3},
+				{ProcessID: 3, BurstDuration: 24},
+			},
+			want: 0,
+		},
+		{
+			name: "staggered arrivals",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 24},
+				{ProcessID: 2, ArrivalTime: 1, BurstDuration: 3},
+			},
+			wantErr: ErrInvalidArgs,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			theory, simulated, err := sjfDelta(tt.processes, defaultSettings())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("sjfDelta() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if theory != tt.want || simulated != tt.want {
+				t.Errorf("sjfDelta() = %v in theory, %v simulated; want %v", theory, simulated, tt.want)
+			}
+		})
+	}
+
+	if got := batchAverageWait([]int64{24, 3, 3}); got != 17 {
+		t.Errorf("batchAverageWait(24, 3, 3) = %v, want 17", got)
+	}
+}
+
+func Test_convoyPenalty(t *testing.T) {
+	t.Parallel()
+	// Largest first waits 0, 100, 101 and 102; shortest first 0, 1, 2, 3.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 1},
+		{ProcessID: 2, BurstDuration: 100},
+		{ProcessID: 3, BurstDuration: 1},
+		{ProcessID: 4, BurstDuration: 1},
+	}
+	convoy, optimal, err := convoyPenalty(processes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if convoy != 75.75 || optimal != 1.5 {
+		t.Errorf("convoyPenalty() = %v, %v; want 75.75, 1.5", convoy, optimal)
+	}
+	if penalty := convoy - optimal; penalty < 70 {
+		t.Errorf("penalty %v is not the large one expected", penalty)
+	}
+
+	if _, _, err := convoyPenalty([]Process{{ProcessID: 1, ArrivalTime: 2, BurstDuration: 1}}); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("convoyPenalty() error = %v, want %v", err, ErrInvalidArgs)
+	}
+}
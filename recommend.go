@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Metric names accepted by -weights. Throughput is the only one where higher
+// is better; the rest are costs.
+const (
+	metricWait       = "wait"
+	metricTurnaround = "turnaround"
+	metricThroughput = "throughput"
+	metricSwitches   = "switches"
+)
+
+// parseWeights parses a -weights value such as
+// "wait=0.5,turnaround=0.3,switches=0.2".
+func parseWeights(v string) (map[string]float64, error) {
+	weights := make(map[string]float64)
+	for _, pair := range strings.Split(v, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: weight %q must look like name=value", ErrInvalidArgs, pair)
+		}
+		switch name {
+		case metricWait, metricTurnaround, metricThroughput, metricSwitches:
+		default:
+			return nil, fmt.Errorf("%w: unknown weight %q, want one of %s, %s, %s, %s",
+				ErrInvalidArgs, name, metricWait, metricTurnaround, metricThroughput, metricSwitches)
+		}
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("%w: weight %q must be a non-negative number", ErrInvalidArgs, pair)
+		}
+		weights[name] = weight
+	}
+
+	return weights, nil
+}
+
+// contextSwitches counts the dispatches that hand the CPU to a different
+// process than the one that ran last.
+func contextSwitches(gantt []TimeSlice) int {
+	var switches int
+	for i := 1; i < len(gantt); i++ {
+		if gantt[i].PID != gantt[i-1].PID {
+			switches++
+		}
+	}
+
+	return switches
+}
+
+// recommend scores every result by its weighted metrics and returns the index
+// of the lowest (best) score along with all scores. Each metric is min-max
+// normalized across the results first, so a weight expresses preference rather
+// than the metric's scale; throughput is inverted so that lower is better
+// everywhere.
+func recommend(results []ScheduleResult, weights map[string]float64) (int, []float64) {
+	values := map[string][]float64{}
+	for i := range results {
+		values[metricWait] = append(values[metricWait], results[i].Metrics.AverageWait)
+		values[metricTurnaround] = append(values[metricTurnaround], results[i].Metrics.AverageTurnaround)
+		values[metricThroughput] = append(values[metricThroughput], results[i].Metrics.Throughput)
+		values[metricSwitches] = append(values[metricSwitches], float64(contextSwitches(results[i].Gantt)))
+	}
+
+	scores := make([]float64, len(results))
+	for name, weight := range weights {
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, v := range values[name] {
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+		if hi == lo {
+			continue
+		}
+		for i, v := range values[name] {
+			norm := (v - lo) / (hi - lo)
+			if name == metricThroughput {
+				norm = 1 - norm
+			}
+			scores[i] += weight * norm
+		}
+	}
+
+	best := 0
+	for i := range scores {
+		if scores[i] < scores[best] {
+			best = i
+		}
+	}
+
+	return best, scores
+}
+
+func outputRecommendation(w io.Writer, results []ScheduleResult, s settings) {
+	if len(results) == 0 {
+		return
+	}
+	weights := s.weights
+	best, scores := recommend(results, weights)
+
+	names := make([]string, 0, len(weights))
+	for name := range weights {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		names[i] = fmt.Sprintf("%s=%.2f", name, weights[name])
+	}
+
+	_, _ = fmt.Fprintf(w, "Weighted scores (%s, lower is better)\n", strings.Join(names, ", "))
+	table := newTable(w, s)
+	table.SetHeader([]string{"Algorithm", "Score"})
+	for i := range results {
+		table.Append([]string{results[i].Title, fmt.Sprintf("%.3f", scores[i])})
+	}
+	table.Render()
+	_, _ = fmt.Fprintf(w, "Recommended: %s\n", results[best].Title)
+}
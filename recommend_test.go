@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_parseWeights(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string]float64
+		wantErr error
+	}{
+		{
+			name:  "success",
+			value: "wait=0.5,turnaround=0.3,switches=0.2",
+			want:  map[string]float64{"wait": 0.5, "turnaround": 0.3, "switches": 0.2},
+		},
+		{
+			name:    "unknown metric",
+			value:   "latency=1",
+			wantErr: ErrInvalidArgs,
+		},
+		{
+			name:    "negative weight",
+			value:   "wait=-1",
+			wantErr: ErrInvalidArgs,
+		},
+		{
+			name:    "missing value",
+			value:   "wait",
+			wantErr: ErrInvalidArgs,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseWeights(tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseWeights() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseWeights() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_recommend(t *testing.T) {
+	t.Parallel()
+	processes := func() []Process {
+		return []Process{
+			{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+			{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+			{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+		}
+	}
+	tests := []struct {
+		name    string
+		weights map[string]float64
+		want    string
+	}{
+		{
+			name:    "wait only",
+			weights: map[string]float64{"wait": 1},
+			want:    "sjf",
+		},
+		{
+			name:    "turnaround only",
+			weights: map[string]float64{"turnaround": 1},
+			want:    "sjf",
+		},
+		{
+			name:    "switches only, ties keep registry order",
+			weights: map[string]float64{"switches": 1},
+			want:    "fcfs",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			results := runSchedulers(processes(), defaultSettings())
+			best, _ := recommend(results, tt.weights)
+			if got := results[best].Name; got != tt.want {
+				t.Errorf("recommend() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_contextSwitches(t *testing.T) {
+	t.Parallel()
+	gantt := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 4},
+		{PID: 1, Start: 4, Stop: 5},
+		{PID: 2, Start: 5, Stop: 9},
+		{PID: 3, Start: 9, Stop: 13},
+		{PID: 2, Start: 13, Stop: 14},
+	}
+	if got := contextSwitches(gantt); got != 3 {
+		t.Errorf("contextSwitches() = %d, want 3", got)
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Renderer writes schedule results in one output format.
+type Renderer interface {
+	Render(w io.Writer, r ScheduleResult) error
+}
+
+// batchRenderer is implemented by formats that describe every result in a
+// single document; they are handed all the results at once.
+type batchRenderer interface {
+	RenderAll(w io.Writer, results []ScheduleResult) error
+}
+
+// sliceCounter is implemented by formats that may draw fewer Gantt slices
+// than a result has, so -dry-render can report what is actually drawn.
+type sliceCounter interface {
+	drawnSlices(r ScheduleResult) int
+}
+
+// renderers lists every -format, in the order help text shows them.
+var renderers = []struct {
+	format string
+	new    func(s settings) Renderer
+}{
+	{format: formatText, new: func(s settings) Renderer { return textRenderer{s} }},
+	{format: formatJSONAll, new: func(settings) Renderer { return jsonAllRenderer{} }},
+	{format: formatChromeTrace, new: func(settings) Renderer { return chromeTraceRenderer{} }},
+	{format: formatDOT, new: func(settings) Renderer { return dotRenderer{} }},
+}
+
+// newRenderer returns the renderer for s.format.
+func newRenderer(s settings) (Renderer, error) {
+	formats := make([]string, len(renderers))
+	for i := range renderers {
+		if renderers[i].format == s.format {
+			return renderers[i].new(s), nil
+		}
+		formats[i] = renderers[i].format
+	}
+
+	return nil, fmt.Errorf("%w: unknown format %q, want one of %s",
+		ErrInvalidArgs, s.format, strings.Join(formats, ", "))
+}
+
+// renderAll writes results with renderer, as one document if its format
+// calls for that.
+func renderAll(w io.Writer, renderer Renderer, results []ScheduleResult) error {
+	if batch, ok := renderer.(batchRenderer); ok {
+		return batch.RenderAll(w, results)
+	}
+	for i := range results {
+		if err := renderer.Render(w, results[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RenderSize is how much output one result renders to, as -dry-render
+// reports it. Slices counts the Gantt slices drawn, which -max-gantt-slices
+// can make fewer than the result has.
+type RenderSize struct {
+	Algorithm string
+	Slices    int
+	Rows      int
+	Bytes     int64
+}
+
+// byteCounter is a writer that only counts what is written to it.
+type byteCounter int64
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	*c += byteCounter(len(p))
+	return len(p), nil
+}
+
+// renderSizes renders each result to a counter instead of printing it, and
+// totals the bytes the whole report would take, which for batch formats is
+// less than the sum of the results rendered one by one. Other formats are
+// rendered only once, so any warnings they log are not repeated.
+func renderSizes(renderer Renderer, results []ScheduleResult) ([]RenderSize, int64, error) {
+	sizes := make([]RenderSize, len(results))
+	var sum int64
+	for i := range results {
+		var counter byteCounter
+		if err := renderer.Render(&counter, results[i]); err != nil {
+			return nil, 0, err
+		}
+		slices := len(results[i].Gantt)
+		if counter, ok := renderer.(sliceCounter); ok {
+			slices = counter.drawnSlices(results[i])
+		}
+		sizes[i] = RenderSize{
+			Algorithm: results[i].Name,
+			Slices:    slices,
+			Rows:      len(results[i].Rows),
+			Bytes:     int64(counter),
+		}
+		sum += int64(counter)
+	}
+	if _, ok := renderer.(batchRenderer); !ok {
+		return sizes, sum, nil
+	}
+
+	var total byteCounter
+	if err := renderAll(&total, renderer, results); err != nil {
+		return nil, 0, err
+	}
+
+	return sizes, int64(total), nil
+}
+
+// dryRender writes, in place of the report, how large it would be.
+func dryRender(w io.Writer, renderer Renderer, results []ScheduleResult) error {
+	sizes, total, err := renderSizes(renderer, results)
+	if err != nil {
+		return err
+	}
+	for _, size := range sizes {
+		_, _ = fmt.Fprintf(w, "%s: %d Gantt slices, %d table rows, %d bytes\n",
+			size.Algorithm, size.Slices, size.Rows, size.Bytes)
+	}
+	_, _ = fmt.Fprintf(w, "total: %d bytes\n", total)
+
+	return nil
+}
+
+// textRenderer is the default human-readable report.
+type textRenderer struct {
+	s settings
+}
+
+func (t textRenderer) Render(w io.Writer, r ScheduleResult) error {
+	outputResult(w, r, t.s)
+	return nil
+}
+
+func (t textRenderer) drawnSlices(r ScheduleResult) int {
+	return shownSlices(len(r.Gantt), t.s)
+}
+
+// jsonAllRenderer writes {"algorithms": [...]}.
+type jsonAllRenderer struct{}
+
+func (jsonAllRenderer) Render(w io.Writer, r ScheduleResult) error {
+	return outputJSONAll(w, []ScheduleResult{r})
+}
+
+func (jsonAllRenderer) RenderAll(w io.Writer, results []ScheduleResult) error {
+	return outputJSONAll(w, results)
+}
+
+// chromeTraceRenderer writes the Trace Event Format read by Perfetto and
+// chrome://tracing.
+type chromeTraceRenderer struct{}
+
+func (chromeTraceRenderer) Render(w io.Writer, r ScheduleResult) error {
+	return outputChromeTrace(w, []ScheduleResult{r})
+}
+
+func (chromeTraceRenderer) RenderAll(w io.Writer, results []ScheduleResult) error {
+	return outputChromeTrace(w, results)
+}
+
+// dotRenderer writes a GraphViz digraph of context switches.
+type dotRenderer struct{}
+
+func (dotRenderer) Render(w io.Writer, r ScheduleResult) error {
+	return outputDOT(w, []ScheduleResult{r})
+}
+
+func (dotRenderer) RenderAll(w io.Writer, results []ScheduleResult) error {
+	return outputDOT(w, results)
+}
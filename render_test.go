@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_newRenderer(t *testing.T) {
+	t.Parallel()
+	s := defaultSettings()
+	s.format = "yaml"
+	_, err := newRenderer(s)
+	if !errors.Is(err, ErrInvalidArgs) {
+		t.Fatalf("newRenderer() error = %v, want %v", err, ErrInvalidArgs)
+	}
+	for _, r := range renderers {
+		if !strings.Contains(err.Error(), r.format) {
+			t.Errorf("error %q does not list format %s", err, r.format)
+		}
+	}
+
+	processes := []Process{{ProcessID: 1, BurstDuration: 5, Priority: 2}}
+	sc, _ := lookupScheduler("fcfs")
+	result := sc.schedule(processes, defaultSettings())
+	var got, want bytes.Buffer
+	renderer, err := newRenderer(defaultSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := renderAll(&got, renderer, []ScheduleResult{result}); err != nil {
+		t.Fatal(err)
+	}
+	outputResult(&want, result, defaultSettings())
+	if got.String() != want.String() {
+		t.Errorf("text renderer wrote\n%s\nwant\n%s", got.String(), want.String())
+	}
+}
+
+func Test_renderSizes(t *testing.T) {
+	t.Parallel()
+	s := defaultSettings()
+	s.quantum = 2
+	s.maxGanttSlices = 3
+	processes := generateWorkload(workloadRRWorst, 6, s.quantum)
+	results := runSchedulers(processes, s)
+	renderer, err := newRenderer(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sizes, total, err := renderSizes(renderer, results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, size := range sizes {
+		// The bar is the line under the Gantt heading, one cell per slice.
+		var report bytes.Buffer
+		if err := renderer.Render(&report, results[i]); err != nil {
+			t.Fatal(err)
+		}
+		_, chart, _ := strings.Cut(report.String(), "Gantt schedule\n")
+		bar, _, _ := strings.Cut(chart, "\n")
+		if drawn := strings.Count(bar, "|") - 1; size.Slices != drawn {
+			t.Errorf("%s: reported %d slices, but %d are drawn:\n%s", size.Algorithm, size.Slices, drawn, report.String())
+		}
+		if len(results[i].Gantt) > s.maxGanttSlices && size.Slices != s.maxGanttSlices {
+			t.Errorf("%s: reported %d slices, want the %d cap", size.Algorithm, size.Slices, s.maxGanttSlices)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := renderAll(&out, renderer, results); err != nil {
+		t.Fatal(err)
+	}
+	if total != int64(out.Len()) {
+		t.Errorf("reported %d bytes, but the report is %d", total, out.Len())
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// ErrSelfTest reports a textbook case whose averages came out wrong.
+var ErrSelfTest = errors.New("self-test failed")
+
+// textbookCase is a canonical workload with averages worked out by hand, as
+// found in operating-systems textbooks (Silberschatz et al., Operating System
+// Concepts, chapter 5, for the CPU-bound examples).
+type textbookCase struct {
+	name           string
+	algorithm      string
+	quantum        int64
+	processes      []Process
+	wantWait       float64
+	wantTurnaround float64
+}
+
+// selfTestTolerance absorbs float error in the hand-worked averages.
+const selfTestTolerance = 0.005
+
+var textbookCases = []textbookCase{
+	{
+		name: "FCFS convoy: 24, 3, 3 at t=0", algorithm: "fcfs",
+		processes: []Process{
+			{ProcessID: 1, BurstDuration: 24},
+			{ProcessID: 2, BurstDuration: 3},
+			{ProcessID: 3, BurstDuration: 3},
+		},
+		// Waits 0, 24 and 27.
+		wantWait: 17, wantTurnaround: 27,
+	},
+	{
+		name: "FCFS short jobs first: 3, 3, 24 at t=0", algorithm: "fcfs",
+		processes: []Process{
+			{ProcessID: 2, BurstDuration: 3},
+			{ProcessID: 3, BurstDuration: 3},
+			{ProcessID: 1, BurstDuration: 24},
+		},
+		// Waits 0, 3 and 6.
+		wantWait: 3, wantTurnaround: 13,
+	},
+	{
+		name: "SJF: 6, 8, 7, 3 at t=0", algorithm: "sjf",
+		processes: []Process{
+			{ProcessID: 1, BurstDuration: 6},
+			{ProcessID: 2, BurstDuration: 8},
+			{ProcessID: 3, BurstDuration: 7},
+			{ProcessID: 4, BurstDuration: 3},
+		},
+		// Run 4, 1, 3, 2, waiting 3, 16, 9 and 0.
+		wantWait: 7, wantTurnaround: 13,
+	},
+	{
+		name: "RR q=4: 24, 3, 3 at t=0", algorithm: "rr", quantum: 4,
+		processes: []Process{
+			{ProcessID: 1, BurstDuration: 24},
+			{ProcessID: 2, BurstDuration: 3},
+			{ProcessID: 3, BurstDuration: 3},
+		},
+		// Waits 6, 4 and 7.
+		wantWait: 17.0 / 3, wantTurnaround: 47.0 / 3,
+	},
+}
+
+// selfTest runs every case, writing a line per case, and fails if any
+// average strays from the textbook's.
+func selfTest(w io.Writer, cases []textbookCase) error {
+	failed := 0
+	for _, tc := range cases {
+		s := defaultSettings()
+		if tc.quantum > 0 {
+			s.quantum = tc.quantum
+		}
+		sc, ok := lookupScheduler(tc.algorithm)
+		if !ok {
+			return fmt.Errorf("%w: %s: unknown algorithm %q", ErrSelfTest, tc.name, tc.algorithm)
+		}
+		m := sc.schedule(append([]Process(nil), tc.processes...), s).Metrics
+
+		status := "ok"
+		if math.Abs(m.AverageWait-tc.wantWait) > selfTestTolerance ||
+			math.Abs(m.AverageTurnaround-tc.wantTurnaround) > selfTestTolerance {
+			status = "FAIL"
+			failed++
+		}
+		_, _ = fmt.Fprintf(w, "%-4s %s: wait %.2f (want %.2f), turnaround %.2f (want %.2f)\n",
+			status, tc.name, m.AverageWait, tc.wantWait, m.AverageTurnaround, tc.wantTurnaround)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d textbook cases", ErrSelfTest, failed, len(cases))
+	}
+
+	return nil
+}
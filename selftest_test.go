@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_selfTest(t *testing.T) {
+	t.Parallel()
+	var w bytes.Buffer
+	if err := selfTest(&w, textbookCases); err != nil {
+		t.Fatalf("selfTest() error = %v\n%s", err, w.String())
+	}
+	for _, algorithm := range []string{"fcfs", "sjf"} {
+		found := false
+		for _, tc := range textbookCases {
+			found = found || tc.algorithm == algorithm
+		}
+		if !found {
+			t.Errorf("no textbook case covers %s", algorithm)
+		}
+	}
+
+	wrong := textbookCases[0]
+	wrong.wantWait++
+	w.Reset()
+	if err := selfTest(&w, []textbookCase{wrong}); !errors.Is(err, ErrSelfTest) {
+		t.Errorf("selfTest() error = %v, want %v", err, ErrSelfTest)
+	}
+	if !strings.HasPrefix(w.String(), "FAIL") {
+		t.Errorf("a wrong average was not marked FAIL:\n%s", w.String())
+	}
+}
@@ -0,0 +1,449 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const defaultQuantum int64 = 4
+
+// maxPrecision is the most decimal places -precision shows: about as many as
+// a float64 holds, and few enough that rounding to them cannot overflow.
+const maxPrecision = 15
+
+// Output formats accepted by -format.
+const (
+	formatText        = "text"
+	formatJSONAll     = "json-all"
+	formatChromeTrace = "chrome-trace"
+	formatDOT         = "dot"
+)
+
+// Rounding modes accepted by -round.
+const (
+	roundNearest = "nearest"
+	roundFloor   = "floor"
+	roundCeil    = "ceil"
+)
+
+// Policies accepted by -error-policy.
+const (
+	errorPolicyFail = "fail"
+	errorPolicySkip = "skip"
+)
+
+// Arrival formats accepted by -arrival-format.
+const (
+	arrivalTicks     = "ticks"
+	arrivalTimestamp = "timestamp"
+)
+
+// Defaults accepted by -missing-priority.
+const (
+	missingLowest  = "lowest"
+	missingHighest = "highest"
+)
+
+// Orders accepted by -rr-requeue-order.
+const (
+	requeueFIFO      = "fifo"
+	requeueRemaining = "remaining"
+)
+
+// Policies accepted by -overflow.
+const (
+	overflowDrop  = "drop"
+	overflowDelay = "delay"
+)
+
+// settings holds everything the command line can tune about a run.
+type settings struct {
+	quantum           int64
+	quantumFrac       float64
+	compareQuanta     []int64
+	preemptOnArrival  bool
+	rrRequeueOrder    string
+	format            string
+	algorithms        []string
+	precision         int
+	rounding          string
+	maxGanttSlices    int
+	ganttProportional bool
+	ganttLabels       bool
+	compactTable      bool
+	showLine          bool
+	validateSum       bool
+	strict            bool
+	noIdleAdvance     bool
+	sjfDelta          bool
+	convoyAnalysis    bool
+	perProcess        bool
+
+	// A positive queueCap bounds the fcfs and rr ready queues; overflow says
+	// what happens to arrivals that find it full.
+	queueCap int
+	overflow string
+
+	// A positive maxTime stops every run at that tick; averages then cover
+	// unfinished processes too unless avgCompletedOnly is set.
+	maxTime          int64
+	avgCompletedOnly bool
+
+	// Ties are broken by PID unless randomTies is set, in which case a
+	// shuffle seeded with tieSeed decides.
+	randomTies bool
+	tieSeed    int64
+
+	// Exponential averaging for the predicted shortest-process-next
+	// scheduler.
+	spnAlpha float64
+	spnTau0  float64
+
+	showWaitingIntervals bool
+	weights              map[string]float64
+	checkMonotonicIDs    bool
+	onlyIDs              []int64
+	errorPolicy          string
+	arrivalFormat        string
+	noPriorityRank       string
+	warningsJSON         bool
+	clampWait            bool
+	parallel             bool
+	statsFile            string
+	throughputWindow     int64
+	histogramBuckets     int
+	dryRender            bool
+	hash                 bool
+	groupEvents          string
+	selfTest             bool
+	summaryTableOnly     bool
+	interleaveOutput     bool
+	trace                bool
+	traceFormat          string
+	arrivalOffset        int64
+	dumpProcesses        bool
+	dumpOnly             bool
+	// warnings is where every warning goes: stderr, as JSON lines under
+	// -warnings-json.
+	warnings warner
+	// stderr is where -trace writes. runFiles points it, and warnings, at a
+	// buffer per file, so -parallel traces come out whole and in file order.
+	stderr io.Writer
+	// pendingStats, when set, receives the -stats-file rows instead of their
+	// being appended, so runFiles can append them in file order.
+	pendingStats *[][]string
+
+	// A workload queried from a database instead of read from a file.
+	db      string
+	dbQuery string
+
+	// Poisson workload generation, enabled by a positive genLambda, or an
+	// adversarial workload named by workload. Both take genCount processes.
+	workload  string
+	genLambda float64
+	// burstDist, when set, generates genCount processes with bursts, and
+	// optionally inter-arrival gaps, drawn from distributions instead.
+	burstDist   *distribution
+	arrivalDist *distribution
+	genMu       float64
+	genCount    int
+	genSeed     int64
+}
+
+func defaultSettings() settings {
+	return settings{
+		quantum:        defaultQuantum,
+		warnings:       warner{w: os.Stderr},
+		stderr:         os.Stderr,
+		format:         formatText,
+		precision:      2,
+		rounding:       roundNearest,
+		errorPolicy:    errorPolicyFail,
+		arrivalFormat:  arrivalTicks,
+		noPriorityRank: missingLowest,
+		overflow:       overflowDrop,
+		rrRequeueOrder: requeueFIFO,
+		traceFormat:    traceText,
+		dbQuery:        "SELECT id, burst, arrival, priority, label FROM processes",
+		spnAlpha:       0.5,
+		spnTau0:        10,
+		genMu:          0.2,
+		genCount:       10,
+		genSeed:        1,
+	}
+}
+
+// parseSettings parses flags from args (args[0] being the binary name) and
+// returns the settings along with the binary name and remaining positional
+// arguments, ready for openProcessingFile.
+func parseSettings(args ...string) (settings, []string, error) {
+	s := defaultSettings()
+	if len(args) == 0 {
+		return s, args, nil
+	}
+
+	var config string
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.StringVar(&config, "config", "",
+		"read settings from this JSON file, keyed by flag name plus input for the file; flags given here win")
+	fs.Int64Var(&s.quantum, "quantum", s.quantum, "round-robin quantum in ticks")
+	fs.Float64Var(&s.quantumFrac, "quantum-frac", 0,
+		"set the round-robin quantum to this fraction of the workload's mean burst, rounded (at least 1)")
+	fs.Func("compare-quanta", "tabulate round-robin at each of these quanta, e.g. 2,4,8, against FCFS and SJF",
+		func(v string) (err error) {
+			s.compareQuanta, err = parseQuanta(v)
+			return err
+		})
+	fs.BoolVar(&s.preemptOnArrival, "round-robin-preempt-on-arrival", false,
+		"preempt the running round-robin job when a higher-priority job arrives")
+	fs.StringVar(&s.rrRequeueOrder, "rr-requeue-order", s.rrRequeueOrder,
+		"order of round-robin jobs joining the queue at the same instant: fifo, or remaining for shortest remaining first")
+	fs.Func("algo", "comma-separated schedulers to run, in order (default fcfs,sjf,priority,rr; also spn)",
+		func(v string) error {
+			s.algorithms = strings.Split(v, ",")
+			for _, name := range s.algorithms {
+				if _, ok := lookupScheduler(name); !ok {
+					return fmt.Errorf("unknown scheduler %q", name)
+				}
+			}
+			return nil
+		})
+	fs.Float64Var(&s.spnAlpha, "spn-alpha", s.spnAlpha,
+		"weight of the most recent burst when spn predicts the next one, in [0, 1]")
+	fs.Float64Var(&s.spnTau0, "spn-tau0", s.spnTau0, "initial burst prediction for spn")
+	fs.StringVar(&s.format, "format", s.format,
+		"output format: text, json-all for one JSON document covering every algorithm, chrome-trace for Perfetto, "+
+			"or dot for a GraphViz graph of context switches")
+	fs.IntVar(&s.precision, "precision", s.precision, "decimal places shown for averages")
+	fs.StringVar(&s.rounding, "round", s.rounding, "how averages are rounded to -precision: nearest, floor or ceil")
+	fs.IntVar(&s.maxGanttSlices, "max-gantt-slices", 0,
+		"draw at most this many Gantt slices (0 for no limit); metrics still cover the full schedule")
+	fs.BoolVar(&s.ganttProportional, "gantt-proportional", false,
+		"draw Gantt cells one column per tick, so widths reflect durations")
+	fs.BoolVar(&s.ganttLabels, "gantt-labels", false, "draw each process's label, where it has one, in the Gantt chart instead of its PID")
+	fs.BoolVar(&s.compactTable, "compact-table", false, "render tables without borders and with tighter spacing")
+	fs.Int64Var(&s.maxTime, "max-time", 0, "stop every run at this tick (0 to run to completion)")
+	fs.BoolVar(&s.avgCompletedOnly, "avg-completed-only", false,
+		"with -max-time, average only over processes that finished, instead of all that arrived")
+	fs.BoolVar(&s.strict, "strict", false, "fail if any schedule runs two slices at once")
+	fs.BoolVar(&s.noIdleAdvance, "no-idle-advance", false,
+		"with -strict, also fail if any schedule leaves the CPU idle, naming the idle interval")
+	fs.BoolVar(&s.validateSum, "validate-sum", false,
+		"fail if any footer average differs from the average recomputed from the table rows")
+	fs.IntVar(&s.queueCap, "queue-cap", 0,
+		"hold at most this many waiting processes in the fcfs and rr ready queues (0 for no limit)")
+	fs.StringVar(&s.overflow, "overflow", s.overflow,
+		"what happens to arrivals that find the ready queue full: drop, or delay until there is room")
+	fs.BoolVar(&s.sjfDelta, "sjf-delta", false,
+		"when every process arrives at 0, show how much SJF cuts the average wait against FCFS, in theory and simulated")
+	fs.BoolVar(&s.convoyAnalysis, "convoy-analysis", false,
+		"when every process arrives at 0, show the wait the convoy effect adds with the largest job first")
+	fs.BoolVar(&s.perProcess, "per-process", false,
+		"add a per-process table of wait, response, turnaround, weighted turnaround and preemptions")
+	fs.BoolVar(&s.showLine, "show-line", false, "add a column giving the input line each process was read from")
+	fs.Func("tie-seed", "break scheduling ties with a random order drawn from this seed instead of by PID",
+		func(v string) (err error) {
+			s.randomTies = true
+			if s.tieSeed, err = strconv.ParseInt(v, 10, 64); err != nil {
+				return fmt.Errorf("bad seed %q", v)
+			}
+			return nil
+		})
+	fs.BoolVar(&s.showWaitingIntervals, "show-waiting-intervals", false,
+		"list the intervals each process spent in the round-robin ready queue")
+	fs.Func("weights", "recommend an algorithm by weighted metrics, e.g. wait=0.5,turnaround=0.3,switches=0.2",
+		func(v string) (err error) {
+			s.weights, err = parseWeights(v)
+			return err
+		})
+	fs.BoolVar(&s.checkMonotonicIDs, "check-monotonic-ids", false,
+		"fail unless process IDs run 1..N in file order")
+	fs.StringVar(&s.errorPolicy, "error-policy", s.errorPolicy,
+		"what to do with malformed input rows: fail, or skip them with a warning")
+	fs.BoolVar(&s.clampWait, "clamp-wait", false,
+		"show any negative wait in the schedule table as 0, warning about each one clamped")
+	fs.BoolVar(&s.warningsJSON, "warnings-json", false,
+		`write warnings to stderr as JSON lines, {"kind": ..., "message": ...}, instead of plain text`)
+	fs.Func("only-ids", "schedule only these process IDs, e.g. 1,3,5", func(v string) (err error) {
+		s.onlyIDs, err = parseIDList(v)
+		return err
+	})
+	fs.BoolVar(&s.parallel, "parallel", false,
+		"schedule every file and algorithm concurrently; output keeps the usual order")
+	fs.Func("turnaround-histogram", "after each table, draw a histogram of turnaround times in buckets=N ranges",
+		func(v string) (err error) {
+			s.histogramBuckets, err = parseHistogramSpec(v)
+			return err
+		})
+	fs.Int64Var(&s.throughputWindow, "throughput-window", 0,
+		"after each table, list throughput over every window of this many ticks (0 for none)")
+	fs.BoolVar(&s.dryRender, "dry-render", false,
+		"instead of the report, print how many Gantt slices, table rows and bytes it would take")
+	fs.BoolVar(&s.hash, "hash", false,
+		"instead of the report, print a stable SHA-256 of each algorithm's schedule, to pin in regression checks")
+	fs.StringVar(&s.statsFile, "stats-file", "",
+		"append each algorithm's average metrics to this CSV file, writing its header if the file is new")
+	fs.BoolVar(&s.trace, "trace", false, "write each scheduling decision, with the ready processes at the time, to stderr")
+	fs.StringVar(&s.traceFormat, "trace-format", s.traceFormat, "how -trace writes decisions: text or json (one object per line)")
+	fs.BoolVar(&s.summaryTableOnly, "summary-table-only", false,
+		"print only a table comparing every algorithm's averages, instead of each algorithm's report")
+	fs.BoolVar(&s.interleaveOutput, "interleave-output", false,
+		"before the reports, stack every algorithm's Gantt chart on one shared time scale")
+	fs.BoolVar(&s.selfTest, "self-test", false,
+		"instead of scheduling a file, check each algorithm's averages against worked textbook examples")
+	fs.StringVar(&s.groupEvents, "group-events", "",
+		"CSV of <Group>,<Arrival Time> events; each grouped process arrives with its group's event")
+	fs.StringVar(&s.arrivalFormat, "arrival-format", s.arrivalFormat,
+		"how input arrivals are written: ticks, or timestamp for RFC3339 times counted in seconds from the earliest")
+	fs.StringVar(&s.noPriorityRank, "missing-priority", s.noPriorityRank,
+		"where rows without a priority rank when others have one: lowest (after all of them) or highest")
+	fs.Int64Var(&s.arrivalOffset, "arrival-offset", 0, "shift every arrival time by this many ticks")
+	fs.BoolVar(&s.dumpProcesses, "dump-processes", false,
+		"print the effective process list as CSV, after filters and offsets, before scheduling")
+	fs.BoolVar(&s.dumpOnly, "dump-only", false, "like -dump-processes, but exit without scheduling")
+	fs.Float64Var(&s.genLambda, "gen-poisson", 0,
+		"instead of reading a file, generate arrivals as a Poisson process with this rate (lambda)")
+	fs.Float64Var(&s.genMu, "gen-service", s.genMu,
+		"service rate (mu) of the exponential burst distribution for -gen-poisson; mean burst is 1/mu")
+	fs.StringVar(&s.db, "db", "",
+		"instead of reading a file, query processes from a database given as driver:dsn (build with -tags sqlite for sqlite)")
+	fs.StringVar(&s.dbQuery, "db-query", s.dbQuery,
+		"query for -db; columns id, burst and arrival are required, priority and label optional")
+	fs.StringVar(&s.workload, "workload", "",
+		"instead of reading a file, generate a known-hard workload: convoy, starve or rr-worst")
+	fs.Func("burst-dist",
+		"instead of reading a file, generate bursts from uniform:min,max, normal:mean,sd or exponential:mean",
+		func(v string) error {
+			d, err := parseDistribution(v)
+			s.burstDist = &d
+			return err
+		})
+	fs.Func("arrival-dist", "with -burst-dist, draw inter-arrival gaps from this distribution (default: all arrive at 0)",
+		func(v string) error {
+			d, err := parseDistribution(v)
+			s.arrivalDist = &d
+			return err
+		})
+	fs.IntVar(&s.genCount, "n", s.genCount, "number of processes to generate")
+	fs.Int64Var(&s.genSeed, "seed", s.genSeed, "random seed for generated workloads")
+	if err := fs.Parse(args[1:]); err != nil {
+		return s, nil, fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+	}
+	positional := fs.Args()
+	if config != "" {
+		files, err := applyConfig(fs, config)
+		if err != nil {
+			return s, nil, err
+		}
+		if len(positional) == 0 {
+			positional = files
+		}
+	}
+	s.warnings.asJSON = s.warningsJSON
+
+	if _, err := newRenderer(s); err != nil {
+		return s, nil, err
+	}
+
+	switch s.rounding {
+	case roundNearest, roundFloor, roundCeil:
+	default:
+		return s, nil, fmt.Errorf("%w: unknown rounding mode %q", ErrInvalidArgs, s.rounding)
+	}
+	switch s.errorPolicy {
+	case errorPolicyFail, errorPolicySkip:
+	default:
+		return s, nil, fmt.Errorf("%w: unknown error policy %q", ErrInvalidArgs, s.errorPolicy)
+	}
+	switch s.arrivalFormat {
+	case arrivalTicks, arrivalTimestamp:
+	default:
+		return s, nil, fmt.Errorf("%w: unknown arrival format %q", ErrInvalidArgs, s.arrivalFormat)
+	}
+	switch s.noPriorityRank {
+	case missingLowest, missingHighest:
+	default:
+		return s, nil, fmt.Errorf("%w: unknown missing-priority default %q", ErrInvalidArgs, s.noPriorityRank)
+	}
+	switch s.traceFormat {
+	case traceText, traceJSON:
+	default:
+		return s, nil, fmt.Errorf("%w: unknown trace format %q", ErrInvalidArgs, s.traceFormat)
+	}
+	switch s.rrRequeueOrder {
+	case requeueFIFO, requeueRemaining:
+	default:
+		return s, nil, fmt.Errorf("%w: unknown round-robin requeue order %q", ErrInvalidArgs, s.rrRequeueOrder)
+	}
+	switch s.overflow {
+	case overflowDrop, overflowDelay:
+	default:
+		return s, nil, fmt.Errorf("%w: unknown overflow policy %q", ErrInvalidArgs, s.overflow)
+	}
+	if s.queueCap < 0 {
+		return s, nil, fmt.Errorf("%w: -queue-cap must not be negative", ErrInvalidArgs)
+	}
+	if s.spnAlpha < 0 || s.spnAlpha > 1 {
+		return s, nil, fmt.Errorf("%w: -spn-alpha must be within [0, 1]", ErrInvalidArgs)
+	}
+	if s.precision < 0 || s.precision > maxPrecision {
+		return s, nil, fmt.Errorf("%w: -precision must be from 0 to %d", ErrInvalidArgs, maxPrecision)
+	}
+	if s.maxTime < 0 {
+		return s, nil, fmt.Errorf("%w: -max-time must not be negative", ErrInvalidArgs)
+	}
+	if s.summaryTableOnly && s.format != formatText {
+		return s, nil, fmt.Errorf("%w: -summary-table-only is a text report and cannot be combined with -format %s",
+			ErrInvalidArgs, s.format)
+	}
+	if s.interleaveOutput && s.format != formatText {
+		return s, nil, fmt.Errorf("%w: -interleave-output is a text chart and cannot be combined with -format %s",
+			ErrInvalidArgs, s.format)
+	}
+	if s.noIdleAdvance && !s.strict {
+		return s, nil, fmt.Errorf("%w: -no-idle-advance is part of -strict and needs it", ErrInvalidArgs)
+	}
+	if s.throughputWindow < 0 {
+		return s, nil, fmt.Errorf("%w: -throughput-window must not be negative", ErrInvalidArgs)
+	}
+	if s.quantum < 1 {
+		return s, nil, fmt.Errorf("%w: -quantum must be at least 1", ErrInvalidArgs)
+	}
+	if s.quantumFrac < 0 {
+		return s, nil, fmt.Errorf("%w: -quantum-frac must not be negative", ErrInvalidArgs)
+	}
+	switch s.workload {
+	case "", workloadConvoy, workloadStarve, workloadRRWorst:
+	default:
+		return s, nil, fmt.Errorf("%w: unknown workload %q", ErrInvalidArgs, s.workload)
+	}
+	if s.arrivalDist != nil && s.burstDist == nil {
+		return s, nil, fmt.Errorf("%w: -arrival-dist needs -burst-dist", ErrInvalidArgs)
+	}
+	if s.burstDist != nil && (s.db != "" || s.workload != "" || s.genLambda > 0 || s.genCount <= 0) {
+		return s, nil, fmt.Errorf(
+			"%w: -burst-dist needs a positive -n and cannot be combined with -db, -workload or -gen-poisson",
+			ErrInvalidArgs)
+	}
+	if s.db != "" && (s.workload != "" || s.genLambda > 0) {
+		return s, nil, fmt.Errorf("%w: -db cannot be combined with a generated workload", ErrInvalidArgs)
+	}
+	if s.workload != "" && (s.genLambda > 0 || s.genCount <= 0) {
+		return s, nil, fmt.Errorf("%w: -workload needs a positive -n and cannot be combined with -gen-poisson",
+			ErrInvalidArgs)
+	}
+	if s.genLambda < 0 || (s.genLambda > 0 && (s.genMu <= 0 || s.genCount <= 0)) {
+		return s, nil, fmt.Errorf("%w: -gen-poisson needs a positive rate, -gen-service and -n", ErrInvalidArgs)
+	}
+	// A rate is one over a mean, which is bounded like any distribution's.
+	if s.genLambda > 0 && !(s.genLambda >= 1/maxDistParam && s.genMu >= 1/maxDistParam) {
+		return s, nil, fmt.Errorf("%w: -gen-poisson and -gen-service rates must be at least 1/%.0f",
+			ErrInvalidArgs, maxDistParam)
+	}
+
+	return s, append(args[:1:1], positional...), nil
+}
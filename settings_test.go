@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_parseSettings(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		args     []string
+		want     settings
+		wantArgs []string
+		wantErr  error
+	}{
+		{
+			name:     "defaults",
+			args:     []string{"binary_name", "file.csv"},
+			want:     defaultSettings(),
+			wantArgs: []string{"binary_name", "file.csv"},
+		},
+		{
+			name: "preempt on arrival",
+			args: []string{"binary_name", "-round-robin-preempt-on-arrival", "file.csv"},
+			want: func() settings {
+				s := defaultSettings()
+				s.preemptOnArrival = true
+				return s
+			}(),
+			wantArgs: []string{"binary_name", "file.csv"},
+		},
+		{
+			name: "json-all format",
+			args: []string{"binary_name", "-format", "json-all", "file.csv"},
+			want: func() settings {
+				s := defaultSettings()
+				s.format = formatJSONAll
+				return s
+			}(),
+			wantArgs: []string{"binary_name", "file.csv"},
+		},
+		{
+			name: "unknown format",
+			args: []string{"binary_name", "-format", "yaml", "file.csv"},
+			want: func() settings {
+				s := defaultSettings()
+				s.format = "yaml"
+				return s
+			}(),
+			wantErr: ErrInvalidArgs,
+		},
+		{
+			name: "largest precision",
+			args: []string{"binary_name", "-precision", "15", "file.csv"},
+			want: func() settings {
+				s := defaultSettings()
+				s.precision = maxPrecision
+				return s
+			}(),
+			wantArgs: []string{"binary_name", "file.csv"},
+		},
+		{
+			name: "precision too large",
+			args: []string{"binary_name", "-precision", "400", "file.csv"},
+			want: func() settings {
+				s := defaultSettings()
+				s.precision = 400
+				return s
+			}(),
+			wantErr: ErrInvalidArgs,
+		},
+		{
+			name:    "unknown flag",
+			args:    []string{"binary_name", "-bogus", "file.csv"},
+			want:    defaultSettings(),
+			wantErr: ErrInvalidArgs,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, gotArgs, err := parseSettings(tt.args...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseSettings() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSettings() = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("parseSettings() args = %v, want %v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func Test_parseSettings_config(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "run.json")
+	config := `{
+		"input": "example_processes.csv",
+		"algo": ["fcfs", "rr"],
+		"quantum": 2,
+		"format": "json-all",
+		"per-process": true,
+		"weights": "wait=1"
+	}`
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	want := defaultSettings()
+	want.algorithms = []string{"fcfs", "rr"}
+	want.quantum = 2
+	want.format = formatJSONAll
+	want.perProcess = true
+	want.weights = map[string]float64{"wait": 1}
+
+	got, args, err := parseSettings("binary_name", "-config", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) || !reflect.DeepEqual(args, []string{"binary_name", "example_processes.csv"}) {
+		t.Errorf("parseSettings() = %+v, %v\nwant %+v, [binary_name example_processes.csv]", got, args, want)
+	}
+
+	// Flags and files on the command line beat the config.
+	want.quantum = 6
+	got, args, err = parseSettings("binary_name", "-quantum", "6", "-config", path, "other.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) || !reflect.DeepEqual(args, []string{"binary_name", "other.csv"}) {
+		t.Errorf("parseSettings() with overrides = %+v, %v\nwant %+v, [binary_name other.csv]", got, args, want)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"quantm": 2}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := parseSettings("binary_name", "-config", path); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("unknown config key: error = %v, want %v", err, ErrInvalidArgs)
+	}
+}
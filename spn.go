@@ -0,0 +1,117 @@
+package main
+
+import "sort"
+
+// nextPrediction applies one step of exponential averaging:
+// τ(n+1) = α·t(n) + (1-α)·τ(n), where t(n) is the burst just observed.
+func nextPrediction(tau float64, burst int64, alpha float64) float64 {
+	return alpha*float64(burst) + (1-alpha)*tau
+}
+
+// predictBursts returns the predictions τ(0)..τ(n) made before each burst in
+// history and after the last one, starting from tau0.
+func predictBursts(history []int64, alpha, tau0 float64) []float64 {
+	predictions := make([]float64, 0, len(history)+1)
+	tau := tau0
+	for _, burst := range history {
+		predictions = append(predictions, tau)
+		tau = nextPrediction(tau, burst, alpha)
+	}
+
+	return append(predictions, tau)
+}
+
+// spnSchedule is shortest-process-next without knowing bursts up front: it
+// dispatches the ready process with the smallest predicted burst and runs it
+// to completion. Processes sharing a label are treated as repeated runs of the
+// same program, so each completed run refines the prediction for the next run
+// of that label by exponential averaging (weight s.spnAlpha, initial guess
+// s.spnTau0). Unlabeled processes have no history and are predicted at tau0.
+func spnSchedule(processes []Process, s settings) ScheduleResult {
+	var (
+		currentTime int64
+		schedule    = make([]ScheduleRow, len(processes))
+		gantt       = make([]TimeSlice, 0, len(processes))
+		predictions = make(map[int64]float64, len(processes))
+		tau         = make(map[string]float64)
+		pending     = make([]int, len(processes))
+	)
+	for i := range pending {
+		pending[i] = i
+	}
+
+	predict := func(p Process) float64 {
+		if t, ok := tau[p.Label]; ok && p.Label != "" {
+			return t
+		}
+		return s.spnTau0
+	}
+
+	ties := s.tieBreaker(processes)
+	for len(pending) > 0 {
+		ready := make([]int, 0, len(pending))
+		next := int64(-1)
+		for _, i := range pending {
+			if processes[i].ArrivalTime <= currentTime {
+				ready = append(ready, i)
+			} else if next < 0 || processes[i].ArrivalTime < next {
+				next = processes[i].ArrivalTime
+			}
+		}
+		if len(ready) == 0 {
+			currentTime = next
+			continue
+		}
+
+		sort.Slice(ready, func(a, b int) bool {
+			pa, pb := processes[ready[a]], processes[ready[b]]
+			if predict(pa) != predict(pb) {
+				return predict(pa) < predict(pb)
+			}
+			if pa.ArrivalTime != pb.ArrivalTime {
+				return pa.ArrivalTime < pb.ArrivalTime
+			}
+			return ties(pa, pb)
+		})
+		chosen := ready[0]
+		for k, i := range pending {
+			if i == chosen {
+				pending = append(pending[:k], pending[k+1:]...)
+				break
+			}
+		}
+
+		p := processes[chosen]
+		predictions[p.ProcessID] = predict(p)
+		if p.Label != "" {
+			tau[p.Label] = nextPrediction(predict(p), p.BurstDuration, s.spnAlpha)
+		}
+
+		start := currentTime
+		currentTime += p.BurstDuration
+		wait := start - p.ArrivalTime
+		turnaround := currentTime - p.ArrivalTime
+
+		schedule[chosen] = ScheduleRow{
+			ProcessID:  p.ProcessID,
+			Priority:   p.Priority,
+			Burst:      p.BurstDuration,
+			Arrival:    p.ArrivalTime,
+			Wait:       wait,
+			Turnaround: turnaround,
+			Exit:       currentTime,
+		}
+		gantt = append(gantt, TimeSlice{
+			PID:   p.ProcessID,
+			Start: start,
+			Stop:  currentTime,
+		})
+	}
+
+	return ScheduleResult{
+		Gantt:       gantt,
+		Rows:        schedule,
+		Predictions: predictions,
+		Metrics:     computeMetrics(processes, gantt),
+	}
+}
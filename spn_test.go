@@ -0,0 +1,61 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func Test_predictBursts(t *testing.T) {
+	t.Parallel()
+	// The classic textbook sequence with α = 1/2 and τ0 = 10.
+	history := []int64{6, 4, 6, 4, 13, 13, 13}
+	want := []float64{10, 8, 6, 6, 5, 9, 11, 12}
+
+	got := predictBursts(history, 0.5, 10)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("predictBursts() = %v, want %v", got, want)
+	}
+
+	// Every prediction follows τ(n+1) = α·t(n) + (1-α)·τ(n).
+	const alpha = 0.3
+	got = predictBursts(history, alpha, 7)
+	for n := range history {
+		if want := alpha*float64(history[n]) + (1-alpha)*got[n]; math.Abs(got[n+1]-want) > 1e-9 {
+			t.Errorf("τ(%d) = %v, want %v", n+1, got[n+1], want)
+		}
+	}
+}
+
+func Test_spnSchedule(t *testing.T) {
+	t.Parallel()
+	// Two programs run repeatedly: "short" bursts 2 and "long" bursts 12.
+	// After one run of each, the predictions separate and the short program's
+	// second run jumps ahead of the long one's.
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 12, Label: "long"},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2, Label: "short"},
+		{ProcessID: 3, ArrivalTime: 2, BurstDuration: 12, Label: "long"},
+		{ProcessID: 4, ArrivalTime: 3, BurstDuration: 2, Label: "short"},
+	}
+	s := defaultSettings()
+	r := spnSchedule(processes, s)
+
+	var order []int64
+	for _, slice := range r.Gantt {
+		order = append(order, slice.PID)
+	}
+	if want := []int64{1, 2, 4, 3}; !reflect.DeepEqual(order, want) {
+		t.Errorf("dispatch order = %v, want %v", order, want)
+	}
+
+	wantPredictions := map[int64]float64{
+		1: 10,
+		2: 10,
+		4: nextPrediction(10, 2, s.spnAlpha),
+		3: nextPrediction(10, 12, s.spnAlpha),
+	}
+	if !reflect.DeepEqual(r.Predictions, wantPredictions) {
+		t.Errorf("predictions = %v, want %v", r.Predictions, wantPredictions)
+	}
+}
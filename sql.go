@@ -0,0 +1,109 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// openDB opens a -db source written as driver:dsn, e.g. sqlite:workload.db.
+// No driver is built in; build with -tags sqlite for SQLite, or register
+// another database/sql driver.
+func openDB(source string) (*sql.DB, error) {
+	driverName, dsn, ok := strings.Cut(source, ":")
+	if !ok || driverName == "" {
+		return nil, fmt.Errorf("%w: -db %q must look like driver:dsn", ErrInvalidArgs, source)
+	}
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("%w: -db: %v (available drivers: %s)",
+			ErrInvalidArgs, err, strings.Join(sql.Drivers(), ", "))
+	}
+
+	return db, nil
+}
+
+// loadProcessesSQL runs s.dbQuery against db and maps each result row to a
+// Process by column name, ignoring case: id (or pid), burst and arrival are
+// required; priority and label are optional, as are their values, so NULLs
+// read as no priority and no label. As with ragged file rows, processes
+// without a priority rank by s.noPriorityRank when others have one.
+func loadProcessesSQL(db *sql.DB, s settings) ([]Process, error) {
+	rows, err := db.Query(s.dbQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%w: querying processes", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading columns", err)
+	}
+	var (
+		ints   = make(map[string]*sql.NullInt64)
+		label  sql.NullString
+		fields = make([]interface{}, len(columns))
+	)
+	for i, column := range columns {
+		name := strings.ToLower(column)
+		if name == "pid" {
+			name = "id"
+		}
+		switch name {
+		case "id", "burst", "arrival", "priority":
+			v := new(sql.NullInt64)
+			ints[name] = v
+			fields[i] = v
+		case "label":
+			fields[i] = &label
+		default:
+			fields[i] = new(interface{})
+		}
+	}
+	for _, required := range []string{"id", "burst", "arrival"} {
+		if ints[required] == nil {
+			return nil, fmt.Errorf("%w: query has no %s column", ErrInvalidRow, required)
+		}
+	}
+
+	processes := make([]Process, 0)
+	var bound horizon
+	for row := 1; rows.Next(); row++ {
+		label = sql.NullString{}
+		for _, v := range ints {
+			*v = sql.NullInt64{}
+		}
+		if err := rows.Scan(fields...); err != nil {
+			return nil, fmt.Errorf("%w: row %d: %v", ErrInvalidRow, row, err)
+		}
+		for _, required := range []string{"id", "burst", "arrival"} {
+			if !ints[required].Valid {
+				return nil, fmt.Errorf("%w: row %d has no %s", ErrInvalidRow, row, required)
+			}
+		}
+
+		p := Process{
+			ProcessID:     ints["id"].Int64,
+			BurstDuration: ints["burst"].Int64,
+			ArrivalTime:   ints["arrival"].Int64,
+			NoPriority:    true,
+			Label:         label.String,
+		}
+		if priority := ints["priority"]; priority != nil && priority.Valid {
+			p.Priority, p.NoPriority = priority.Int64, false
+		}
+		if err := checkTimes(p, row); err != nil {
+			return nil, err
+		}
+		if err := bound.add(p, row); err != nil {
+			return nil, err
+		}
+		processes = append(processes, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: reading processes", err)
+	}
+	defaultPriorities(processes, s.noPriorityRank)
+
+	return processes, nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// fakeTables are the result sets served by the fake driver, keyed by DSN.
+var fakeTables = map[string]struct {
+	columns []string
+	rows    [][]driver.Value
+}{
+	"three": {
+		columns: []string{"ID", "burst", "arrival", "priority", "label"},
+		rows: [][]driver.Value{
+			{int64(1), int64(5), int64(0), int64(2), "build"},
+			{int64(2), int64(9), int64(3), nil, nil},
+			{int64(3), int64(6), int64(6), int64(3), "test"},
+		},
+	},
+	"no burst": {
+		columns: []string{"pid", "arrival"},
+		rows:    [][]driver.Value{{int64(1), int64(0)}},
+	},
+	"null arrival": {
+		columns: []string{"pid", "burst", "arrival"},
+		rows:    [][]driver.Value{{int64(1), int64(5), nil}},
+	},
+}
+
+func init() {
+	sql.Register("fakeprocesses", fakeDriver{})
+}
+
+// fakeDriver is a read-only database/sql driver answering every query with
+// the fakeTables entry named by its DSN.
+type (
+	fakeDriver struct{}
+	fakeConn   struct{ table string }
+	fakeStmt   struct{ table string }
+	fakeRows   struct {
+		columns []string
+		rows    [][]driver.Value
+	}
+)
+
+func (fakeDriver) Open(dsn string) (driver.Conn, error) { return fakeConn{dsn}, nil }
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) { return fakeStmt(c), nil }
+func (fakeConn) Close() error                          { return nil }
+func (fakeConn) Begin() (driver.Tx, error)             { return nil, errors.New("read-only") }
+
+func (fakeStmt) Close() error  { return nil }
+func (fakeStmt) NumInput() int { return -1 }
+func (fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("read-only")
+}
+
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	t := fakeTables[s.table]
+	return &fakeRows{columns: t.columns, rows: t.rows}, nil
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func Test_loadProcessesSQL(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		source         string
+		noPriorityRank string
+		want           []Process
+		wantErr        error
+	}{
+		{
+			name:   "three rows",
+			source: "fakeprocesses:three",
+			want: []Process{
+				{ProcessID: 1, BurstDuration: 5, ArrivalTime: 0, Priority: 2, Label: "build"},
+				{ProcessID: 2, BurstDuration: 9, ArrivalTime: 3, Priority: 4, NoPriority: true},
+				{ProcessID: 3, BurstDuration: 6, ArrivalTime: 6, Priority: 3, Label: "test"},
+			},
+		},
+		{
+			name:           "null priority ranks highest",
+			source:         "fakeprocesses:three",
+			noPriorityRank: missingHighest,
+			want: []Process{
+				{ProcessID: 1, BurstDuration: 5, ArrivalTime: 0, Priority: 2, Label: "build"},
+				{ProcessID: 2, BurstDuration: 9, ArrivalTime: 3, Priority: 1, NoPriority: true},
+				{ProcessID: 3, BurstDuration: 6, ArrivalTime: 6, Priority: 3, Label: "test"},
+			},
+		},
+		{
+			name:    "missing column",
+			source:  "fakeprocesses:no burst",
+			wantErr: ErrInvalidRow,
+		},
+		{
+			name:    "null required value",
+			source:  "fakeprocesses:null arrival",
+			wantErr: ErrInvalidRow,
+		},
+		{
+			name:    "unknown driver",
+			source:  "nosuchdriver:x",
+			wantErr: ErrInvalidArgs,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			db, err := openDB(tt.source)
+			if err == nil {
+				defer func() { _ = db.Close() }()
+				s := defaultSettings()
+				if tt.noPriorityRank != "" {
+					s.noPriorityRank = tt.noPriorityRank
+				}
+				var got []Process
+				got, err = loadProcessesSQL(db, s)
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("loadProcessesSQL() = %+v, want %+v", got, tt.want)
+				}
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
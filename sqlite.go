@@ -0,0 +1,6 @@
+//go:build sqlite
+
+package main
+
+// Registers the "sqlite" driver, so -db sqlite:workload.db works.
+import _ "modernc.org/sqlite"
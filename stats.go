@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// statsHeader is the first row of every -stats-file.
+var statsHeader = []string{
+	"source", "algorithm", "quantum", "average_wait", "average_contention_wait", "average_turnaround", "throughput",
+}
+
+// statsRows returns the -stats-file rows for results: one per result, naming
+// the workload by source, its file or how it was generated. The quantum column
+// is left blank for algorithms that do not use one.
+func statsRows(source string, results []ScheduleResult, s settings) [][]string {
+	rows := make([][]string, 0, len(results))
+	for _, r := range results {
+		quantum := ""
+		if r.Name == "rr" {
+			quantum = strconv.FormatInt(s.quantum, 10)
+		}
+		rows = append(rows, []string{
+			source,
+			r.Name,
+			quantum,
+			strconv.FormatFloat(r.Metrics.AverageWait, 'f', -1, 64),
+			strconv.FormatFloat(r.Metrics.AverageContentionWait, 'f', -1, 64),
+			strconv.FormatFloat(r.Metrics.AverageTurnaround, 'f', -1, 64),
+			strconv.FormatFloat(r.Metrics.Throughput, 'f', -1, 64),
+		})
+	}
+
+	return rows
+}
+
+// appendStats appends rows to the CSV file at path, writing the header first
+// if the file is new or empty.
+func appendStats(path string, rows [][]string) (err error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("%v: error opening stats file", err)
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("%v: error closing stats file", closeErr)
+		}
+	}()
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("%v: error reading stats file", err)
+	}
+	if info.Size() == 0 {
+		rows = append([][]string{statsHeader}, rows...)
+	}
+
+	cw := csv.NewWriter(f)
+	if err := cw.WriteAll(rows); err != nil {
+		return fmt.Errorf("%v: error writing stats file", err)
+	}
+
+	return nil
+}
+
+// statsSource names the workload args and s describe, for -stats-file.
+func statsSource(s settings, args []string) string {
+	switch {
+	case s.workload != "":
+		return "workload:" + s.workload
+	case s.genLambda > 0:
+		return "poisson"
+	case s.burstDist != nil:
+		return "distributions"
+	case s.db != "":
+		return "db"
+	case len(args) > 1:
+		return args[1]
+	}
+
+	return ""
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_appendStats(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "results.csv")
+	for i := 0; i < 2; i++ {
+		var w bytes.Buffer
+		if err := run(&w, "binary_name", "-algo", "rr", "-stats-file", path, "example_processes.csv"); err != nil {
+			t.Fatalf("run %d: error = %v", i+1, err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want a header and two data rows: %v", len(records), records)
+	}
+	if !reflect.DeepEqual(records[0], statsHeader) {
+		t.Errorf("header = %v, want %v", records[0], statsHeader)
+	}
+	for _, row := range records[1:] {
+		if row[0] != "example_processes.csv" || row[1] != "rr" || row[2] != "4" {
+			t.Errorf("row = %v, want source example_processes.csv, algorithm rr and quantum 4", row)
+		}
+	}
+	if !reflect.DeepEqual(records[1], records[2]) {
+		t.Errorf("identical runs wrote different rows: %v and %v", records[1], records[2])
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// ProcessSummary gathers one process's key metrics for the -per-process
+// table.
+type ProcessSummary struct {
+	ProcessID int64 `json:"pid"`
+	Wait      int64 `json:"wait"`
+	// Response is how long the process waited for its first dispatch, or
+	// nil if -max-time stopped the run before it was ever dispatched.
+	Response   *int64 `json:"response,omitempty"`
+	Turnaround int64  `json:"turnaround"`
+	// WeightedTurnaround is turnaround over burst: 1 for a process that
+	// never waited, higher the longer it was held up relative to its size.
+	// It is 0 for a zero-burst process.
+	WeightedTurnaround float64 `json:"weightedTurnaround"`
+	// Preemptions counts how often another process was given the CPU
+	// before this one finished. Being stopped by -max-time is not a
+	// preemption.
+	Preemptions int `json:"preemptions"`
+}
+
+// summarizeProcesses derives a summary for each row of r, in table order,
+// from the Gantt chart and the original processes. Unfinished processes are
+// measured up to maxTime.
+func summarizeProcesses(r ScheduleResult, processes []Process, maxTime int64) []ProcessSummary {
+	byPID := make(map[int64]Process, len(processes))
+	for _, p := range processes {
+		byPID[p.ProcessID] = p
+	}
+	slices := make(map[int64][]TimeSlice, len(processes))
+	// switchedAt holds when each process handed the CPU to another, the
+	// switches contextSwitches counts.
+	switchedAt := make(map[int64][]int64, len(processes))
+	for i, slice := range r.Gantt {
+		slices[slice.PID] = append(slices[slice.PID], slice)
+		if i+1 < len(r.Gantt) && r.Gantt[i+1].PID != slice.PID {
+			switchedAt[slice.PID] = append(switchedAt[slice.PID], slice.Stop)
+		}
+	}
+
+	summaries := make([]ProcessSummary, 0, len(r.Rows))
+	for _, row := range r.Rows {
+		p := byPID[row.ProcessID]
+		ran := slices[p.ProcessID]
+
+		var (
+			cpu, end    int64
+			response    *int64
+			preemptions int
+		)
+		for _, slice := range ran {
+			cpu += slice.Stop - slice.Start
+			if slice.Stop > end {
+				end = slice.Stop
+			}
+			if response == nil || slice.Start-p.ArrivalTime < *response {
+				v := slice.Start - p.ArrivalTime
+				response = &v
+			}
+		}
+		// Handing the CPU over before finishing is a preemption; running
+		// on into another slice of its own is not. An unfinished process
+		// is only preempted before the cap, which is not itself one.
+		for _, stop := range switchedAt[p.ProcessID] {
+			if stop < end || row.Unfinished && stop < maxTime {
+				preemptions++
+			}
+		}
+		if row.Unfinished {
+			end = maxTime
+		}
+
+		turnaround := end - p.ArrivalTime
+		summaries = append(summaries, ProcessSummary{
+			ProcessID:          p.ProcessID,
+			Wait:               turnaround - cpu,
+			Response:           response,
+			Turnaround:         turnaround,
+			WeightedTurnaround: average(float64(turnaround), float64(p.BurstDuration)),
+			Preemptions:        preemptions,
+		})
+	}
+
+	return summaries
+}
+
+// outputProcessSummary renders the -per-process table.
+func outputProcessSummary(w io.Writer, summaries []ProcessSummary, s settings) {
+	_, _ = fmt.Fprintln(w, "Per-process summary")
+	table := newTable(w, s)
+	table.SetHeader([]string{"ID", "Wait", "Response", "Turnaround", "Weighted turnaround", "Preemptions"})
+	for _, sum := range summaries {
+		response := "-"
+		if sum.Response != nil {
+			response = fmt.Sprint(*sum.Response)
+		}
+		table.Append([]string{
+			fmt.Sprint(sum.ProcessID),
+			fmt.Sprint(sum.Wait),
+			response,
+			fmt.Sprint(sum.Turnaround),
+			formatAverage(sum.WeightedTurnaround, s),
+			fmt.Sprint(sum.Preemptions),
+		})
+	}
+	table.Render()
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_summarizeProcesses(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+	}
+	s := defaultSettings()
+	s.perProcess = true
+	sc, _ := lookupScheduler("rr")
+	r := sc.schedule(append([]Process(nil), processes...), s)
+
+	// Round-robin runs 1 0-4 4-5, 2 5-9 9-13 17-18, 3 13-17 18-20. Only
+	// 2 at 13 and 3 at 17 hand the CPU over before finishing.
+	want := []ProcessSummary{
+		{ProcessID: 1, Wait: 0, Response: int64Ptr(0), Turnaround: 5, WeightedTurnaround: 1},
+		{ProcessID: 2, Wait: 6, Response: int64Ptr(2), Turnaround: 15, WeightedTurnaround: 15.0 / 9, Preemptions: 1},
+		{ProcessID: 3, Wait: 8, Response: int64Ptr(7), Turnaround: 14, WeightedTurnaround: 14.0 / 6, Preemptions: 1},
+	}
+	if !reflect.DeepEqual(r.Summary, want) {
+		t.Fatalf("Summary = %+v, want %+v", r.Summary, want)
+	}
+
+	var w bytes.Buffer
+	outputProcessSummary(&w, r.Summary, s)
+	lines := strings.Split(strings.TrimSpace(w.String()), "\n")
+	// Title, border, header, border, one line per process, border.
+	if got := len(lines) - 5; got != len(processes) {
+		t.Errorf("table has %d rows, want %d:\n%s", got, len(processes), w.String())
+	}
+	for _, column := range []string{"WAIT", "RESPONSE", "TURNAROUND", "WEIGHTED TURNAROUND", "PREEMPTIONS"} {
+		if !strings.Contains(lines[2], column) {
+			t.Errorf("header lacks %s: %s", column, lines[2])
+		}
+	}
+}
+
+func Test_summarizeProcesses_singleRR(t *testing.T) {
+	t.Parallel()
+	s := defaultSettings()
+	s.perProcess = true
+	sc, _ := lookupScheduler("rr")
+	r := sc.schedule([]Process{{ProcessID: 1, ArrivalTime: 2, BurstDuration: 10}}, s)
+
+	// A lone process runs 2-6 6-10 10-12 without ever giving up the CPU.
+	if len(r.Gantt) != 3 || contextSwitches(r.Gantt) != 0 {
+		t.Fatalf("Gantt = %v, want three slices and no context switch", r.Gantt)
+	}
+	want := []ProcessSummary{
+		{ProcessID: 1, Wait: 0, Response: int64Ptr(0), Turnaround: 10, WeightedTurnaround: 1},
+	}
+	if !reflect.DeepEqual(r.Summary, want) {
+		t.Errorf("Summary = %+v, want %+v", r.Summary, want)
+	}
+}
+
+func Test_summarizeProcesses_maxTime(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		algorithm string
+		processes []Process
+		want      []ProcessSummary
+	}{
+		{
+			// 1 runs 0-5 when the cap stops it; 2 never gets the CPU.
+			name:      "cut off and never dispatched",
+			algorithm: "fcfs",
+			processes: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 10, Priority: 1},
+				{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2, Priority: 1},
+			},
+			want: []ProcessSummary{
+				{ProcessID: 1, Wait: 0, Response: int64Ptr(0), Turnaround: 5, WeightedTurnaround: 0.5},
+				{ProcessID: 2, Wait: 4, Turnaround: 4, WeightedTurnaround: 2},
+			},
+		},
+		{
+			// 1 runs 0-4 and is preempted for 2, which the cap stops at 5.
+			name:      "preempted before the cap",
+			algorithm: "rr",
+			processes: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 10, Priority: 1},
+				{ProcessID: 2, ArrivalTime: 0, BurstDuration: 10, Priority: 1},
+			},
+			want: []ProcessSummary{
+				{ProcessID: 1, Wait: 1, Response: int64Ptr(0), Turnaround: 5, WeightedTurnaround: 0.5, Preemptions: 1},
+				{ProcessID: 2, Wait: 4, Response: int64Ptr(4), Turnaround: 5, WeightedTurnaround: 0.5},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := defaultSettings()
+			s.perProcess = true
+			s.maxTime = 5
+			sc, _ := lookupScheduler(tt.algorithm)
+			r := sc.schedule(tt.processes, s)
+			if !reflect.DeepEqual(r.Summary, tt.want) {
+				t.Errorf("Summary = %+v, want %+v", r.Summary, tt.want)
+			}
+		})
+	}
+
+	var w bytes.Buffer
+	outputProcessSummary(&w, []ProcessSummary{{ProcessID: 2, Wait: 4, Turnaround: 4}}, defaultSettings())
+	if row := strings.Split(w.String(), "\n")[4]; !strings.Contains(row, "| -") {
+		t.Errorf("never-dispatched row does not show its response as -: %s", row)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// tieBreaker reports whether a should go before b when the scheduling policy
+// itself considers them equal.
+type tieBreaker func(a, b Process) bool
+
+// tieByPID is the default strategy: lower process IDs go first.
+func tieByPID(a, b Process) bool {
+	return a.ProcessID < b.ProcessID
+}
+
+// tieBySeed ranks the processes with a shuffle drawn from seed, so ties are
+// broken at random but identically on every run with the same seed and
+// workload. Ranks are assigned in PID order, making them independent of the
+// input's row order.
+func tieBySeed(seed int64, processes []Process) tieBreaker {
+	pids := make([]int64, len(processes))
+	for i := range processes {
+		pids[i] = processes[i].ProcessID
+	}
+	sort.Slice(pids, func(i, j int) bool { return pids[i] < pids[j] })
+
+	perm := rand.New(rand.NewSource(seed)).Perm(len(pids))
+	rank := make(map[int64]int, len(pids))
+	for i, pid := range pids {
+		rank[pid] = perm[i]
+	}
+
+	return func(a, b Process) bool {
+		if rank[a.ProcessID] != rank[b.ProcessID] {
+			return rank[a.ProcessID] < rank[b.ProcessID]
+		}
+		return tieByPID(a, b)
+	}
+}
+
+// tieBreaker returns the tie-break strategy selected by the settings.
+func (s settings) tieBreaker(processes []Process) tieBreaker {
+	if s.randomTies {
+		return tieBySeed(s.tieSeed, processes)
+	}
+
+	return tieByPID
+}
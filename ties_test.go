@@ -0,0 +1,60 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_tieBreaker(t *testing.T) {
+	t.Parallel()
+	processes := func() []Process {
+		return []Process{
+			{ProcessID: 1, ArrivalTime: 0, BurstDuration: 3, Priority: 1},
+			{ProcessID: 2, ArrivalTime: 0, BurstDuration: 3, Priority: 1},
+			{ProcessID: 3, ArrivalTime: 0, BurstDuration: 3, Priority: 1},
+			{ProcessID: 4, ArrivalTime: 0, BurstDuration: 3, Priority: 1},
+		}
+	}
+	dispatched := func(s settings) []int64 {
+		r := sjfSchedule(processes(), s)
+		pids := make([]int64, len(r.Gantt))
+		for i := range r.Gantt {
+			pids[i] = r.Gantt[i].PID
+		}
+		return pids
+	}
+
+	tests := []struct {
+		name       string
+		randomTies bool
+		seed       int64
+		want       []int64
+	}{
+		{
+			name: "by PID",
+			want: []int64{1, 2, 3, 4},
+		},
+		{
+			name:       "seed 7",
+			randomTies: true,
+			seed:       7,
+			want:       []int64{2, 3, 1, 4},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := defaultSettings()
+			s.randomTies = tt.randomTies
+			s.tieSeed = tt.seed
+			got := dispatched(s)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("dispatch order = %v, want %v", got, tt.want)
+			}
+			if again := dispatched(s); !reflect.DeepEqual(again, got) {
+				t.Errorf("dispatch order changed between runs: %v then %v", got, again)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Formats accepted by -trace-format.
+const (
+	traceText = "text"
+	traceJSON = "json"
+)
+
+// TraceStep is one scheduling decision: at Time the processes in Ready were
+// waiting for the CPU (or holding it) and Dispatched got it. The text and
+// JSON tracers both write these.
+type TraceStep struct {
+	Algorithm  string  `json:"algorithm"`
+	Time       int64   `json:"t"`
+	Ready      []int64 `json:"ready"`
+	Dispatched int64   `json:"dispatched"`
+}
+
+// decisionTrace replays the result's Gantt chart as one step per slice. A
+// process counts as ready from its arrival until its last slice stops, or
+// for good if it never finished.
+func decisionTrace(r ScheduleResult) []TraceStep {
+	lastStop := make(map[int64]int64)
+	for _, slice := range r.Gantt {
+		if slice.Stop > lastStop[slice.PID] {
+			lastStop[slice.PID] = slice.Stop
+		}
+	}
+
+	steps := make([]TraceStep, len(r.Gantt))
+	for i, slice := range r.Gantt {
+		ready := make([]int64, 0)
+		for _, row := range r.Rows {
+			if row.Arrival <= slice.Start && (row.Unfinished || lastStop[row.ProcessID] > slice.Start) {
+				ready = append(ready, row.ProcessID)
+			}
+		}
+		sort.Slice(ready, func(a, b int) bool { return ready[a] < ready[b] })
+		steps[i] = TraceStep{Algorithm: r.Name, Time: slice.Start, Ready: ready, Dispatched: slice.PID}
+	}
+
+	return steps
+}
+
+// writeTrace writes the result's decision trace to w, one step per line, as
+// text or, with asJSON, as JSON.
+func writeTrace(w io.Writer, r ScheduleResult, asJSON bool) error {
+	for _, step := range decisionTrace(r) {
+		if !asJSON {
+			_, _ = fmt.Fprintf(w, "%s t=%d ready=%v dispatched=%d\n", step.Algorithm, step.Time, step.Ready, step.Dispatched)
+			continue
+		}
+		line, err := json.Marshal(step)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(w, "%s\n", line)
+	}
+
+	return nil
+}
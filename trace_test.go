@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func Test_writeTrace_json(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 3},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2},
+		{ProcessID: 3, ArrivalTime: 2, BurstDuration: 1},
+	}
+	s := defaultSettings()
+	s.quantum = 2
+	rr, _ := lookupScheduler("rr")
+	r := rr.schedule(processes, s)
+
+	var out bytes.Buffer
+	if err := writeTrace(&out, r, true); err != nil {
+		t.Fatalf("writeTrace() error = %v", err)
+	}
+
+	var steps []TraceStep
+	lines := bufio.NewScanner(&out)
+	for lines.Scan() {
+		var step TraceStep
+		if err := json.Unmarshal(lines.Bytes(), &step); err != nil {
+			t.Fatalf("line %q is not a trace step: %v", lines.Text(), err)
+		}
+		steps = append(steps, step)
+	}
+
+	// P1 runs 0-2 and is requeued ahead of P2 and P3, which are only
+	// admitted once the slice ends; it finishes at 3, then P2 and P3 run.
+	want := []TraceStep{
+		{Algorithm: "rr", Time: 0, Ready: []int64{1}, Dispatched: 1},
+		{Algorithm: "rr", Time: 2, Ready: []int64{1, 2, 3}, Dispatched: 1},
+		{Algorithm: "rr", Time: 3, Ready: []int64{2, 3}, Dispatched: 2},
+		{Algorithm: "rr", Time: 5, Ready: []int64{3}, Dispatched: 3},
+	}
+	if !reflect.DeepEqual(steps, want) {
+		t.Errorf("trace = %+v, want %+v", steps, want)
+	}
+}
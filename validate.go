@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var ErrInconsistentSchedule = errors.New("inconsistent schedule")
+
+// ErrIdleCPU reports a schedule that left the CPU idle under -no-idle-advance.
+var ErrIdleCPU = errors.New("CPU left idle")
+
+// verifyFooterSums recomputes the footer averages from the table rows and
+// checks they render the same as the footer does, at s's precision and
+// rounding. Rows of unfinished processes are left out when the footer leaves
+// them out too.
+func verifyFooterSums(r ScheduleResult, s settings) error {
+	var count, wait, contention, turnaround float64
+	for _, row := range r.Rows {
+		if row.Unfinished && s.avgCompletedOnly {
+			continue
+		}
+		wait += float64(row.Wait)
+		contention += float64(row.ContentionWait)
+		turnaround += float64(row.Turnaround)
+		count++
+	}
+
+	checks := []struct {
+		column         string
+		rows, rendered float64
+	}{
+		{"wait", average(wait, count), r.Metrics.AverageWait},
+		{"contention", average(contention, count), r.Metrics.AverageContentionWait},
+		{"turnaround", average(turnaround, count), r.Metrics.AverageTurnaround},
+	}
+	for _, c := range checks {
+		if got, want := formatAverage(c.rendered, s), formatAverage(c.rows, s); got != want {
+			return fmt.Errorf("%w: %s footer shows average %s %s, but its rows average %s",
+				ErrInconsistentSchedule, r.Name, c.column, got, want)
+		}
+	}
+
+	return nil
+}
+
+// verifyNoOverlap checks that no two Gantt slices share any time, as a single
+// CPU can only run one process at once. Slices that merely touch, one
+// stopping as the next starts, are fine.
+func verifyNoOverlap(r ScheduleResult) error {
+	slices := append([]TimeSlice(nil), r.Gantt...)
+	sort.SliceStable(slices, func(i, j int) bool { return slices[i].Start < slices[j].Start })
+	for i := 1; i < len(slices); i++ {
+		prev, cur := slices[i-1], slices[i]
+		if cur.Start < prev.Stop {
+			return fmt.Errorf("%w: %s runs PID %d from %d to %d, overlapping PID %d from %d to %d",
+				ErrInconsistentSchedule, r.Name, cur.PID, cur.Start, cur.Stop, prev.PID, prev.Start, prev.Stop)
+		}
+		if cur.Stop < prev.Stop {
+			// Keep the furthest-reaching slice for the next comparison.
+			slices[i] = prev
+		}
+	}
+
+	return nil
+}
+
+// verifyNoIdle checks the CPU is busy from tick 0 until the last slice stops,
+// naming the first idle interval if not. A gap means the workload's arrivals
+// are not back-to-back, rather than a scheduler bug.
+func verifyNoIdle(r ScheduleResult) error {
+	var lastStop int64
+	for _, slice := range r.Gantt {
+		if slice.Start > lastStop {
+			return fmt.Errorf("%w: %s idles from %d to %d waiting for PID %d to arrive",
+				ErrIdleCPU, r.Name, lastStop, slice.Start, slice.PID)
+		}
+		if slice.Stop > lastStop {
+			lastStop = slice.Stop
+		}
+	}
+
+	return nil
+}
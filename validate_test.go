@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// verifyNonPreemptive checks the invariants every non-preemptive schedule of
+// a whole workload must hold: each process runs in exactly one slice, that slice lasts exactly
+// the process's burst, and its table row exits when the slice stops.
+func verifyNonPreemptive(r ScheduleResult, processes []Process) error {
+	slices := make(map[int64][]TimeSlice, len(processes))
+	for i := range r.Gantt {
+		slices[r.Gantt[i].PID] = append(slices[r.Gantt[i].PID], r.Gantt[i])
+	}
+	exits := make(map[int64]int64, len(r.Rows))
+	for i := range r.Rows {
+		exits[r.Rows[i].ProcessID] = r.Rows[i].Exit
+	}
+
+	for _, p := range processes {
+		ran := slices[p.ProcessID]
+		if len(ran) != 1 {
+			return fmt.Errorf("%w: %s ran PID %d in %d slices, want 1",
+				ErrInconsistentSchedule, r.Name, p.ProcessID, len(ran))
+		}
+		if want := ran[0].Start + p.BurstDuration; ran[0].Stop != want {
+			return fmt.Errorf("%w: %s ran PID %d from %d to %d, want completion at start+burst=%d",
+				ErrInconsistentSchedule, r.Name, p.ProcessID, ran[0].Start, ran[0].Stop, want)
+		}
+		if exit, ok := exits[p.ProcessID]; !ok || exit != ran[0].Stop {
+			return fmt.Errorf("%w: %s reports PID %d exiting at %d, but its slice stops at %d",
+				ErrInconsistentSchedule, r.Name, p.ProcessID, exit, ran[0].Stop)
+		}
+	}
+
+	return nil
+}
+
+func Test_verifyNonPreemptive(t *testing.T) {
+	t.Parallel()
+	processes := func() []Process {
+		return []Process{
+			{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+			{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+			{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+			{ProcessID: 4, ArrivalTime: 30, BurstDuration: 2, Priority: 1},
+		}
+	}
+	for _, sc := range schedulers {
+		sc := sc
+		if sc.name == "rr" {
+			continue
+		}
+		t.Run(sc.name, func(t *testing.T) {
+			t.Parallel()
+			r := sc.schedule(processes(), defaultSettings())
+			if err := verifyNonPreemptive(r, processes()); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	t.Run("broken schedule is flagged", func(t *testing.T) {
+		t.Parallel()
+		r := ScheduleResult{
+			Name: "broken",
+			Gantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 5},
+				{PID: 2, Start: 5, Stop: 12},
+			},
+			Rows: []ScheduleRow{
+				{ProcessID: 1, Exit: 5},
+				{ProcessID: 2, Exit: 12},
+			},
+		}
+		err := verifyNonPreemptive(r, []Process{
+			{ProcessID: 1, BurstDuration: 5},
+			{ProcessID: 2, BurstDuration: 9},
+		})
+		if !errors.Is(err, ErrInconsistentSchedule) {
+			t.Errorf("verifyNonPreemptive() error = %v, want %v", err, ErrInconsistentSchedule)
+		}
+	})
+}
+
+func Test_verifyFooterSums(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+	}
+	sc, _ := lookupScheduler("fcfs")
+	r := sc.schedule(processes, defaultSettings())
+	if err := verifyFooterSums(r, defaultSettings()); err != nil {
+		t.Fatal(err)
+	}
+
+	r.Rows[2].Turnaround++
+	if err := verifyFooterSums(r, defaultSettings()); !errors.Is(err, ErrInconsistentSchedule) {
+		t.Errorf("verifyFooterSums() error = %v, want %v", err, ErrInconsistentSchedule)
+	}
+}
+
+func Test_verifyNoOverlap(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		gantt   []TimeSlice
+		wantErr error
+	}{
+		{
+			name:  "touching slices",
+			gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 4}, {PID: 2, Start: 4, Stop: 6}, {PID: 1, Start: 8, Stop: 9}},
+		},
+		{
+			name:    "overlap",
+			gantt:   []TimeSlice{{PID: 1, Start: 0, Stop: 4}, {PID: 2, Start: 3, Stop: 6}},
+			wantErr: ErrInconsistentSchedule,
+		},
+		{
+			name:    "nested inside a long slice, out of order",
+			gantt:   []TimeSlice{{PID: 3, Start: 12, Stop: 13}, {PID: 1, Start: 0, Stop: 20}, {PID: 2, Start: 2, Stop: 3}},
+			wantErr: ErrInconsistentSchedule,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := verifyNoOverlap(ScheduleResult{Name: "test", Gantt: tt.gantt})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("verifyNoOverlap() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+		{ProcessID: 4, ArrivalTime: 30, BurstDuration: 2, Priority: 1},
+	}
+	for _, sc := range schedulers {
+		r := sc.schedule(append([]Process(nil), processes...), defaultSettings())
+		if err := verifyNoOverlap(r); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func Test_verifyNoIdle(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		wantErr   error
+	}{
+		{
+			name: "back to back",
+			processes: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 2},
+				{ProcessID: 2, ArrivalTime: 1, BurstDuration: 5},
+			},
+		},
+		{
+			name: "gap in arrivals",
+			processes: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5},
+				{ProcessID: 2, ArrivalTime: 8, BurstDuration: 6},
+			},
+			wantErr: ErrIdleCPU,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			for _, sc := range schedulers {
+				err := verifyNoIdle(sc.schedule(tt.processes, defaultSettings()))
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("%s: verifyNoIdle() error = %v, want %v", sc.name, err, tt.wantErr)
+				}
+				if err != nil && !strings.Contains(err.Error(), "from 5 to 8") {
+					t.Errorf("%s: error %q does not name the idle interval 5 to 8", sc.name, err)
+				}
+			}
+		})
+	}
+
+	var w bytes.Buffer
+	err := run(&w, "binary_name", "-no-idle-advance", "example_processes.csv")
+	if !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("-no-idle-advance without -strict: error = %v, want %v", err, ErrInvalidArgs)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Kinds of warning.
+const (
+	warnSkippedRow  = "skipped-row"
+	warnZeroBurst   = "zero-burst"
+	warnClampedWait = "clamped-wait"
+)
+
+// Warning is a non-fatal problem, as written by -warnings-json.
+type Warning struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// warner reports every warning a run raises, to w (stderr in the CLI), either
+// as "warning: ..." lines or, with asJSON, as one JSON Warning per line.
+type warner struct {
+	w      io.Writer
+	asJSON bool
+}
+
+// warn reports one warning of the given kind.
+func (wr warner) warn(kind, msg string) {
+	if !wr.asJSON {
+		_, _ = fmt.Fprintln(wr.w, "warning:", msg)
+		return
+	}
+	// Encoding a struct of two strings cannot fail.
+	line, _ := json.Marshal(Warning{Kind: kind, Message: msg})
+	_, _ = fmt.Fprintf(wr.w, "%s\n", line)
+}
+
+// of adapts wr to callbacks that only report messages of a single kind.
+func (wr warner) of(kind string) func(string) {
+	return func(msg string) { wr.warn(kind, msg) }
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+func Test_warner(t *testing.T) {
+	t.Parallel()
+	input := "1,5,0,2\n2,nine,3,1\n3,6,3,3\n4,x,1,1\n"
+	s := defaultSettings()
+	s.errorPolicy = errorPolicySkip
+
+	t.Run("json lines", func(t *testing.T) {
+		t.Parallel()
+		var stderr bytes.Buffer
+		if _, err := loadProcesses(strings.NewReader(input), s, warner{w: &stderr, asJSON: true}.of(warnSkippedRow)); err != nil {
+			t.Fatal(err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(stderr.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("got %d warning lines, want 2:\n%s", len(lines), stderr.String())
+		}
+		for i, line := range lines {
+			var w Warning
+			if err := json.Unmarshal([]byte(line), &w); err != nil {
+				t.Fatalf("line %d is not JSON: %v: %s", i+1, err, line)
+			}
+			if w.Kind != warnSkippedRow || !strings.Contains(w.Message, "skipping") {
+				t.Errorf("line %d = %+v, want a %s warning", i+1, w, warnSkippedRow)
+			}
+		}
+	})
+
+	t.Run("plain text", func(t *testing.T) {
+		t.Parallel()
+		var stderr bytes.Buffer
+		if _, err := loadProcesses(strings.NewReader(input), s, warner{w: &stderr}.of(warnSkippedRow)); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Count(stderr.String(), "warning: skipping"); got != 2 {
+			t.Errorf("got %d plain warnings, want 2:\n%s", got, stderr.String())
+		}
+	})
+}
+
+func Test_outputSchedule_clampWait(t *testing.T) {
+	t.Parallel()
+	rows := []ScheduleRow{
+		{ProcessID: 1, Burst: 4, Wait: 0, Turnaround: 4, Exit: 4},
+		{ProcessID: 2, Burst: 3, Arrival: 6, FirstStart: int64Ptr(4), Wait: -2, Turnaround: 1, Exit: 7},
+	}
+
+	var out, stderr bytes.Buffer
+	s := defaultSettings()
+	s.clampWait = true
+	s.warnings = warner{w: &stderr}
+	metrics := Metrics{AverageWait: 1.25}
+	outputSchedule(&out, rows, metrics, s)
+
+	if strings.Contains(out.String(), "-2") {
+		t.Errorf("table still shows the negative wait:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "1.25") || !strings.Contains(out.String(), "0.00") {
+		t.Errorf("footer does not average the clamped waits to 0.00:\n%s", out.String())
+	}
+	if want := "warning: clamped PID 2's wait of -2 to 0\n"; stderr.String() != want {
+		t.Errorf("warnings = %q, want %q", stderr.String(), want)
+	}
+
+	// -dry-render measures the table without logging each clamp twice.
+	stderr.Reset()
+	r := ScheduleResult{Name: "fcfs", Rows: rows, Metrics: metrics}
+	if err := dryRender(io.Discard, textRenderer{s}, []ScheduleResult{r}); err != nil {
+		t.Fatalf("dryRender() error = %v", err)
+	}
+	if want := "warning: clamped PID 2's wait of -2 to 0\n"; stderr.String() != want {
+		t.Errorf("-dry-render warnings = %q, want %q", stderr.String(), want)
+	}
+
+	out.Reset()
+	stderr.Reset()
+	s.clampWait = false
+	outputSchedule(&out, rows, metrics, s)
+	if !strings.Contains(out.String(), "-2") || stderr.Len() > 0 {
+		t.Errorf("without -clamp-wait the wait changed or a warning was logged:\n%s%s", out.String(), stderr.String())
+	}
+	if !strings.Contains(out.String(), "1.25") {
+		t.Errorf("without -clamp-wait the footer is not the computed average:\n%s", out.String())
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// WindowThroughput is the throughput over one -throughput-window: the
+// processes completing in (Start, Stop] divided by the window's width.
+type WindowThroughput struct {
+	Start       int64
+	Stop        int64
+	Completions int
+	Throughput  float64
+}
+
+// throughputSeries slides a window of the given width across the result's
+// timeline one tick at a time, from 0 until it covers the last completion,
+// counting the completions inside each position. A window wider than that is
+// narrowed to end at the last completion, leaving the single window (0, end].
+func throughputSeries(r ScheduleResult, width int64) []WindowThroughput {
+	var exits []int64
+	var end int64
+	for _, row := range r.Rows {
+		if row.Unfinished {
+			continue
+		}
+		exits = append(exits, row.Exit)
+		if row.Exit > end {
+			end = row.Exit
+		}
+	}
+
+	if end > 0 && width > end {
+		width = end
+	}
+
+	var series []WindowThroughput
+	for start := int64(0); start == 0 || start <= end-width; start++ {
+		window := WindowThroughput{Start: start, Stop: start + width}
+		for _, exit := range exits {
+			if exit > window.Start && exit <= window.Stop {
+				window.Completions++
+			}
+		}
+		window.Throughput = float64(window.Completions) / float64(width)
+		series = append(series, window)
+	}
+
+	return series
+}
+
+// outputThroughputWindow lists the -throughput-window series, one window per
+// line, headed by the width the windows actually have.
+func outputThroughputWindow(w io.Writer, series []WindowThroughput, s settings) {
+	width := s.throughputWindow
+	if len(series) > 0 {
+		width = series[0].Stop - series[0].Start
+	}
+	_, _ = fmt.Fprintf(w, "Throughput over %d-tick windows\n", width)
+	for _, window := range series {
+		_, _ = fmt.Fprintf(w, "(%d, %d]: %d completed, %s/t\n",
+			window.Start, window.Stop, window.Completions, formatAverage(window.Throughput, s))
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_throughputSeries(t *testing.T) {
+	t.Parallel()
+	// Three short jobs finish together at 7, 8 and 9, well after the long
+	// job that finishes at 5 and before the one that finishes at 20.
+	r := ScheduleResult{Rows: []ScheduleRow{
+		{ProcessID: 1, Exit: 5},
+		{ProcessID: 2, Exit: 7},
+		{ProcessID: 3, Exit: 8},
+		{ProcessID: 4, Exit: 9},
+		{ProcessID: 5, Exit: 20},
+		{ProcessID: 6, Exit: 14, Unfinished: true},
+	}}
+
+	series := throughputSeries(r, 3)
+	if got, want := len(series), 18; got != want {
+		t.Fatalf("got %d windows, want %d", got, want)
+	}
+	if last := series[len(series)-1]; last.Stop != 20 {
+		t.Errorf("last window ends at %d, want the last completion, 20", last.Stop)
+	}
+
+	peak := series[0]
+	for _, window := range series {
+		if window.Throughput > peak.Throughput {
+			peak = window
+		}
+	}
+	if peak.Start != 6 || peak.Completions != 3 || peak.Throughput != 1 {
+		t.Errorf("peak window = %+v, want (6, 9] with all 3 of the clustered completions", peak)
+	}
+}
+
+func Test_throughputSeries_wideWindow(t *testing.T) {
+	t.Parallel()
+	r := ScheduleResult{Rows: []ScheduleRow{
+		{ProcessID: 1, Exit: 4},
+		{ProcessID: 2, Exit: 10},
+	}}
+
+	for _, width := range []int64{10, 11, math.MaxInt64 - 1, math.MaxInt64} {
+		series := throughputSeries(r, width)
+		want := WindowThroughput{Start: 0, Stop: 10, Completions: 2, Throughput: 0.2}
+		if len(series) != 1 || series[0] != want {
+			t.Errorf("throughputSeries(width %d) = %+v, want the single window %+v", width, series, want)
+		}
+	}
+}